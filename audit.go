@@ -0,0 +1,130 @@
+// audit.go
+//
+// # Chronos Logging - Tamper-Evident Audit Chain
+//
+// With `Config.AuditChain`, every line written to a text or JSON log file
+// ends with a hash linking it to the line before: the SHA-256 of the previous
+// line's hash followed by this line's content. Editing, removing, or
+// reordering a line breaks the chain from that point on, which VerifyChain
+// detects. Each file has its own chain, starting from an empty hash; a file
+// reopened after a restart continues from its last line.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Markers that precede the hash at the end of text and JSON lines.
+const (
+	textHashMarker = "hash="
+	jsonHashMarker = `,"hash":"`
+)
+
+// chainHash returns the hex SHA-256 of prev followed by content.
+func chainHash(prev, content string) string {
+	sum := sha256.Sum256([]byte(prev + content))
+	return hex.EncodeToString(sum[:])
+}
+
+// chain appends the next hash in h's chain to line (which ends with the
+// configured line ending) and advances the chain.
+func (l *Logging) chain(h *logHandle, line string) string {
+	ending := l.lineEnding()
+	body := strings.TrimSuffix(line, ending)
+	if l.config.Format == FormatJSON {
+		content := strings.TrimSuffix(body, "}") + jsonHashMarker
+		h.hash = chainHash(h.hash, content)
+		return content + h.hash + `"}` + ending
+	}
+	content := body + l.separator() + textHashMarker
+	h.hash = chainHash(h.hash, content)
+	return content + h.hash + ending
+}
+
+// lastChainHash returns the hash ending the last line of the file at path,
+// so a reopened file continues its chain. It returns "" for a missing or
+// empty file.
+func lastChainHash(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return ""
+	}
+	// The hash is the last 64 hex digits of the file, before the line
+	// ending and, for JSON, the closing `"}`.
+	size := int64(128)
+	if info.Size() < size {
+		size = info.Size()
+	}
+	buf := make([]byte, size)
+	if _, err := f.ReadAt(buf, info.Size()-size); err != nil && err != io.EOF {
+		return ""
+	}
+	tail := strings.TrimRight(string(buf), "\r\n")
+	tail = strings.TrimSuffix(tail, `"}`)
+	if len(tail) < sha256.Size*2 {
+		return ""
+	}
+	return tail[len(tail)-sha256.Size*2:]
+}
+
+// VerifyChain checks the audit chain of a text or JSON log file written with
+// `Config.AuditChain`. It returns nil if every line's hash matches, or an
+// error naming the first line that does not, which is where the file was
+// altered.
+func VerifyChain(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	prev, pending := "", ""
+	for n := 1; ; n++ {
+		line, err := reader.ReadString('\n')
+		if line == "" && err != nil {
+			if err != io.EOF {
+				return err
+			}
+			if pending != "" {
+				return fmt.Errorf("%s: line %d: missing hash", path, n-1)
+			}
+			return nil
+		}
+		raw := line
+		line = strings.TrimRight(line, "\r\n")
+		marker, suffix := textHashMarker, ""
+		if strings.HasPrefix(pending+line, "{") {
+			marker, suffix = jsonHashMarker, `"}`
+		}
+		i := strings.LastIndex(line, marker)
+		if i < 0 || !strings.HasSuffix(line, suffix) {
+			// A continuation line of a multi-line message.
+			pending += raw
+			continue
+		}
+		content := pending + line[:i+len(marker)]
+		got := strings.TrimSuffix(line[i+len(marker):], suffix)
+		if want := chainHash(prev, content); got != want {
+			return fmt.Errorf("%s: line %d: hash mismatch, chain broken", path, n)
+		}
+		prev, pending = got, ""
+	}
+}
@@ -0,0 +1,81 @@
+// audit_test.go
+//
+// # Chronos Logging - Audit Chain Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// writeAuditChain logs a few entries with AuditChain in format, across a
+// restart so the chain continues in the reopened file, and returns the file.
+func writeAuditChain(t *testing.T, format LogFormat) string {
+	t.Helper()
+	Stop()
+	tempDir := t.TempDir()
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.Format = format
+	cfg.AuditChain = true
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	path := PathFor(clock())
+	Info("user alice logged in")
+	WithFields(Fields{"role": "admin"}).Warn("user alice granted admin")
+	Stop()
+
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	Info("user alice logged out")
+	Stop()
+	return path
+}
+
+// TestVerifyChain writes a chain in text and JSON and asserts it verifies.
+func TestVerifyChain(t *testing.T) {
+	for _, format := range []LogFormat{FormatText, FormatJSON} {
+		path := writeAuditChain(t, format)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := strings.Count(string(content), "hash"); n != 3 {
+			t.Errorf("%s: expected 3 hashed lines, got %q", format, content)
+		}
+		if err := VerifyChain(path); err != nil {
+			t.Errorf("%s: expected the chain to verify, got %v", format, err)
+		}
+	}
+}
+
+// TestVerifyChainDetectsEdit edits a line after the fact and asserts
+// verification fails at that line.
+func TestVerifyChainDetectsEdit(t *testing.T) {
+	for _, format := range []LogFormat{FormatText, FormatJSON} {
+		path := writeAuditChain(t, format)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		edited := strings.Replace(string(content), "granted admin", "granted guest", 1)
+		if err := os.WriteFile(path, []byte(edited), 0644); err != nil {
+			t.Fatal(err)
+		}
+		err = VerifyChain(path)
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("%s: expected a broken chain at line 2, got %v", format, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// batch_test.go
+//
+// # Chronos Logging - Write Batching Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingFile is a memFile that counts Write calls.
+type countingFile struct {
+	memFile
+	writes atomic.Int64
+}
+
+func (c *countingFile) Write(p []byte) (int, error) {
+	c.writes.Add(1)
+	return c.memFile.Write(p)
+}
+
+// startCountingLogger starts a logger writing to a single countingFile.
+func startCountingLogger(cfg *Config) (*countingFile, func()) {
+	Stop()
+	f := &countingFile{}
+	l := newLogging(cfg, logLevels()[INFO])
+	l.opener = func(string, int, os.FileMode) (io.WriteCloser, error) { return f, nil }
+	l.console = newConsoleWriter(io.Discard, false)
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+	return f, func() {
+		Stop()
+		<-done
+	}
+}
+
+// TestBatchWindow asserts a burst is written in fewer calls with every entry
+// present, and that a lone entry is written once the window passes.
+func TestBatchWindow(t *testing.T) {
+	cfg := getConfig()
+	cfg.BatchWindow = 50 * time.Millisecond
+	f, stop := startCountingLogger(cfg)
+
+	const n = 20
+	for i := 0; i < n; i++ {
+		Infof("burst %d", i)
+	}
+	if !waitFor(t, time.Second, func() bool { return strings.Count(f.String(), "\n") == n }) {
+		t.Fatalf("expected %d lines, got %q", n, f.String())
+	}
+	if writes := f.writes.Load(); writes >= n {
+		t.Errorf("expected batched writes, got %d writes for %d entries", writes, n)
+	}
+	for i := 0; i < n; i++ {
+		if !strings.Contains(f.String(), fmt.Sprintf("burst %d\n", i)) {
+			t.Errorf("missing entry %d", i)
+		}
+	}
+
+	start := time.Now()
+	Info("lone entry")
+	if !waitFor(t, time.Second, func() bool { return strings.Contains(f.String(), "lone entry") }) {
+		t.Fatal("lone entry was not written")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("lone entry took %v, expected about the window", elapsed)
+	}
+	stop()
+}
+
+// benchmarkWrites logs b.N entries in bursts and reports file writes per
+// entry.
+func benchmarkWrites(b *testing.B, window time.Duration) {
+	cfg := getConfig()
+	cfg.BatchWindow = window
+	f, stop := startCountingLogger(cfg)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Info("benchmark entry")
+	}
+	logger.Load().flush()
+	b.StopTimer()
+	stop()
+	b.ReportMetric(float64(f.writes.Load())/float64(b.N), "writes/entry")
+}
+
+// BenchmarkWritesUnbatched writes each entry with its own call.
+func BenchmarkWritesUnbatched(b *testing.B) { benchmarkWrites(b, 0) }
+
+// BenchmarkWritesBatched gathers entries within a 5ms window.
+func BenchmarkWritesBatched(b *testing.B) { benchmarkWrites(b, 5*time.Millisecond) }
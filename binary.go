@@ -0,0 +1,154 @@
+// binary.go
+//
+// # Chronos Logging - Binary Format
+//
+// Implements the compact length-prefixed encoding selected with
+// `Config.Format = FormatBinary`, and `DecodeFile` to read it back.
+//
+// Each record is a uvarint body length followed by the body:
+//
+//	level    1 byte, the level's severity
+//	delta    signed varint, nanoseconds since the previous record
+//	message  uvarint length + bytes
+//	fields   uvarint count, then uvarint length + bytes for each key and value
+//
+// A record with level 0 is a reset marker whose body is only a signed varint
+// holding an absolute Unix time in nanoseconds. The writer emits one each time
+// it opens a file, so deltas never span processes or reopened files.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// errTruncatedRecord reports a record cut short, typically one still being
+// written.
+var errTruncatedRecord = errors.New("truncated binary log record")
+
+// encodeBinary appends the record(s) for log to buf. last is the timestamp
+// of the previous record in the file, or zero if none has been written, in
+// which case a reset marker is emitted first.
+func encodeBinary(buf []byte, log Log, last time.Time, order []string) []byte {
+	if last.IsZero() {
+		var reset []byte
+		reset = append(reset, 0)
+		reset = binary.AppendVarint(reset, log.TimeStamp.UnixNano())
+		buf = binary.AppendUvarint(buf, uint64(len(reset)))
+		buf = append(buf, reset...)
+		last = log.TimeStamp
+	}
+
+	var body []byte
+	body = append(body, byte(levelSeverity(logLevels()[log.Level])))
+	body = binary.AppendVarint(body, log.TimeStamp.UnixNano()-last.UnixNano())
+	body = appendBytes(body, log.Message)
+	body = binary.AppendUvarint(body, uint64(len(log.Fields)))
+	for _, k := range fieldKeys(log.Fields, order) {
+		body = appendBytes(body, k)
+		body = appendBytes(body, fmt.Sprint(log.Fields[k]))
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(body)))
+	return append(buf, body...)
+}
+
+// appendBytes appends s to buf prefixed with its uvarint length.
+func appendBytes(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// DecodeFile reads a log file written with `FormatBinary` and returns its
+// entries in order. Field values are returned as strings. If the file ends
+// with an incomplete record, the entries before it are returned with an
+// error.
+func DecodeFile(path string) ([]Log, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	logs, err := decodeBinary(data)
+	if err != nil {
+		return logs, fmt.Errorf("%s: %w", path, err)
+	}
+	return logs, nil
+}
+
+// decodeBinary decodes every record in data.
+func decodeBinary(data []byte) ([]Log, error) {
+	logs := []Log{}
+	var last int64
+	for len(data) > 0 {
+		size, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < size {
+			return logs, errTruncatedRecord
+		}
+		body := data[n : n+int(size)]
+		data = data[n+int(size):]
+
+		if len(body) == 0 {
+			return logs, errors.New("empty binary log record")
+		}
+		severity := int(body[0])
+		body = body[1:]
+		delta, n := binary.Varint(body)
+		if n <= 0 {
+			return logs, errors.New("malformed binary log timestamp")
+		}
+		body = body[n:]
+		if severity == 0 {
+			last = delta
+			continue
+		}
+		last += delta
+
+		level, ok := levelForSeverity(severity)
+		if !ok {
+			return logs, fmt.Errorf("unknown level severity %d", severity)
+		}
+		log := Log{TimeStamp: time.Unix(0, last), Level: level}
+		var err error
+		if log.Message, body, err = consumeBytes(body); err != nil {
+			return logs, err
+		}
+		count, n := binary.Uvarint(body)
+		if n <= 0 {
+			return logs, errors.New("malformed binary log field count")
+		}
+		body = body[n:]
+		if count > 0 {
+			log.Fields = make(Fields, count)
+		}
+		for i := uint64(0); i < count; i++ {
+			var k, v string
+			if k, body, err = consumeBytes(body); err != nil {
+				return logs, err
+			}
+			if v, body, err = consumeBytes(body); err != nil {
+				return logs, err
+			}
+			log.Fields[k] = v
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+// consumeBytes reads a uvarint length-prefixed string from the front of b.
+func consumeBytes(b []byte) (string, []byte, error) {
+	size, n := binary.Uvarint(b)
+	if n <= 0 || uint64(len(b)-n) < size {
+		return "", b, errors.New("malformed binary log string")
+	}
+	return string(b[n : n+int(size)]), b[n+int(size):], nil
+}
@@ -0,0 +1,87 @@
+// binary_test.go
+//
+// # Chronos Logging - Binary Format Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestBinaryRoundTrip writes entries in the binary format across two logger
+// runs appending to the same file and decodes them back.
+func TestBinaryRoundTrip(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "binary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	base := time.Date(2025, 3, 1, 10, 0, 0, 0, time.Local)
+	want := []Log{
+		{TimeStamp: base, Level: INFO, Message: "started"},
+		{TimeStamp: base.Add(1500 * time.Microsecond), Level: WARN, Message: "slow", Fields: Fields{"ms": "1500", "op": "read"}},
+		{TimeStamp: base.Add(time.Second), Level: ERROR, Message: ""},
+		{TimeStamp: base.Add(time.Minute), Level: DEBUG, Message: "after restart"},
+	}
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.Format = FormatBinary
+	for _, batch := range [][]Log{want[:3], want[3:]} {
+		Stop()
+		l := newLogging(cfg, logLevels()[DEBUG])
+		logger.Store(l)
+		done := make(chan struct{})
+		go func() {
+			l.start()
+			close(done)
+		}()
+		for _, log := range batch {
+			l.addLog(log)
+		}
+		Stop()
+		<-done
+	}
+
+	l := newLogging(cfg, logLevels()[DEBUG])
+	got, err := DecodeFile(l.filePathFor(base))
+	if err != nil {
+		t.Fatalf("DecodeFile failed: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if !got[i].TimeStamp.Equal(want[i].TimeStamp) {
+			t.Errorf("entry %d: expected time %v, got %v", i, want[i].TimeStamp, got[i].TimeStamp)
+		}
+		got[i].TimeStamp = want[i].TimeStamp
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("entry %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+// TestDecodeTruncated asserts a partial trailing record is reported while the
+// complete entries before it are returned.
+func TestDecodeTruncated(t *testing.T) {
+	log := Log{TimeStamp: time.Now(), Level: INFO, Message: "complete"}
+	data := encodeBinary(nil, log, time.Time{}, nil)
+	data = encodeBinary(data, log, log.TimeStamp, nil)
+	logs, err := decodeBinary(data[:len(data)-2])
+	if err != errTruncatedRecord {
+		t.Errorf("expected truncation error, got %v", err)
+	}
+	if len(logs) != 1 || logs[0].Message != "complete" {
+		t.Errorf("expected the complete entry, got %+v", logs)
+	}
+}
@@ -0,0 +1,35 @@
+// buffer.go
+//
+// # Chronos Logging - Buffered File Writes
+//
+// With `Config.FlushInterval` or `Config.FlushThreshold` set, file output is
+// held in memory and written out when the interval elapses or that many
+// entries have accumulated, whichever comes first. This trades a bounded
+// window of data at risk on a crash for fewer writes. Stop, Snapshot, flush
+// barriers, and entries waiting on their result (ErrorSync) always flush.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+// buffered reports whether file output is held until a flush. It never is
+// with `Config.SyncForTest`, where every entry is written before the call
+// that logged it returns.
+func (l *Logging) buffered() bool {
+	if l.config.SyncForTest {
+		return false
+	}
+	return l.config.FlushInterval > 0 || l.config.FlushThreshold > 0
+}
+
+// countPending records an entry buffered for the files and flushes once
+// `Config.FlushThreshold` entries have accumulated.
+func (l *Logging) countPending() {
+	l.pendingEntries++
+	if t := l.config.FlushThreshold; t > 0 && l.pendingEntries >= t {
+		l.flushPending()
+	}
+}
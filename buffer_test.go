@@ -0,0 +1,92 @@
+// buffer_test.go
+//
+// # Chronos Logging - Buffered File Write Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFlushThreshold buffers with a long interval and a threshold of 10, and
+// asserts nothing reaches disk for 9 entries but all 10 do on the tenth,
+// without waiting for the interval.
+func TestFlushThreshold(t *testing.T) {
+	Stop()
+	tempDir, err := os.MkdirTemp("", "threshold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.FlushInterval = time.Hour
+	cfg.FlushThreshold = 10
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+	path := logger.Load().filePathFor(time.Now())
+	lines := func() int {
+		content, _ := os.ReadFile(path)
+		return strings.Count(string(content), "\n")
+	}
+
+	for i := 0; i < 9; i++ {
+		Infof("entry %d", i)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if n := lines(); n != 0 {
+		t.Fatalf("expected output to be buffered, found %d lines on disk", n)
+	}
+
+	Info("entry 9")
+	if !waitFor(t, time.Second, func() bool { return lines() == 10 }) {
+		t.Fatalf("expected 10 lines on disk after the tenth entry, got %d", lines())
+	}
+
+	Info("entry 10")
+	time.Sleep(100 * time.Millisecond)
+	if n := lines(); n != 10 {
+		t.Errorf("expected the eleventh entry to be buffered, got %d lines", n)
+	}
+}
+
+// TestFlushInterval asserts buffered output reaches disk once the interval
+// elapses.
+func TestFlushInterval(t *testing.T) {
+	Stop()
+	fs := newMemFS()
+	cfg := getConfig()
+	cfg.FlushInterval = 50 * time.Millisecond
+	l := newLogging(cfg, logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+	defer func() {
+		Stop()
+		<-done
+	}()
+
+	now := time.Now()
+	Info("held")
+	if !waitFor(t, time.Second, func() bool {
+		f := fs.file(l.filePathFor(now))
+		return f != nil && strings.Contains(f.String(), "\theld\n")
+	}) {
+		t.Fatal("expected the entry to be flushed by the interval")
+	}
+}
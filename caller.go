@@ -0,0 +1,98 @@
+// caller.go
+//
+// # Chronos Logging - Caller Information
+//
+// Finds the frame that logged an entry by walking the stack past this
+// package's own frames, and adds the caller details requested by
+// `Config.IncludeModule` and `Config.IncludeCaller` as fields.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// CallerStyle selects how `Config.IncludeCaller` renders the caller.
+type CallerStyle string
+
+// Supported caller styles.
+//
+// - CallerStyleString => one "caller" field, e.g. "app/server.go:42" (default)
+// - CallerStyleFields => "caller.file", "caller.line", and "caller.func" fields
+const (
+	CallerStyleString CallerStyle = "string"
+	CallerStyleFields CallerStyle = "fields"
+)
+
+// Field keys holding caller information.
+const (
+	callerField     = "caller"
+	callerFileField = "caller.file"
+	callerLineField = "caller.line"
+	callerFuncField = "caller.func"
+)
+
+// callerFrame returns the first frame outside this package. Frames in this
+// package's tests count as callers.
+func callerFrame() (runtime.Frame, bool) {
+	var pcs [32]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if packageOf(frame.Function) != chronosPackage || strings.HasSuffix(frame.File, "_test.go") {
+			return frame, true
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// shortFile trims a source path to its directory and file name, e.g.
+// "server/handler.go".
+func shortFile(file string) string {
+	dir, name := filepath.Split(file)
+	return filepath.Base(dir) + "/" + name
+}
+
+// shortFunc strips the package path from a function symbol, e.g.
+// "github.com/acme/app.(*Server).Run" becomes "(*Server).Run".
+func shortFunc(function string) string {
+	return strings.TrimPrefix(function[len(packageOf(function)):], ".")
+}
+
+// withCaller returns fields with the caller details enabled by the config
+// added. Fields the entry already sets are kept. fields is not modified.
+func (l *Logging) withCaller(fields Fields) Fields {
+	frame, ok := callerFrame()
+	if !ok {
+		return fields
+	}
+	tagged := make(Fields, len(fields)+3)
+	if l.config.IncludeModule {
+		tagged[moduleField] = packageOf(frame.Function)
+	}
+	if l.config.IncludeCaller {
+		file := shortFile(frame.File)
+		if l.config.CallerStyle == CallerStyleFields {
+			tagged[callerFileField] = file
+			tagged[callerLineField] = frame.Line
+			tagged[callerFuncField] = shortFunc(frame.Function)
+		} else {
+			tagged[callerField] = file + ":" + strconv.Itoa(frame.Line)
+		}
+	}
+	for k, v := range fields {
+		tagged[k] = v
+	}
+	return tagged
+}
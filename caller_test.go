@@ -0,0 +1,95 @@
+// caller_test.go
+//
+// # Chronos Logging - Caller Information Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// logWithCaller logs one entry and returns the file and line it was logged
+// from.
+func logWithCaller() (string, int) {
+	_, file, line, _ := runtime.Caller(0)
+	Warnf("disk %d%% full", 91)
+	return file, line + 1
+}
+
+// TestCallerStyleFields asserts the caller file, line, and short function
+// name appear as separate JSON fields, and as one "caller" field by default.
+func TestCallerStyleFields(t *testing.T) {
+	for _, style := range []CallerStyle{CallerStyleFields, ""} {
+		Stop()
+		fs := newMemFS()
+		cfg := getConfig()
+		cfg.Format = FormatJSON
+		cfg.IncludeCaller = true
+		cfg.CallerStyle = style
+		if _, err := resolveConfig(cfg); err != nil {
+			t.Fatal(err)
+		}
+		l := newLogging(cfg, logLevels()[INFO])
+		l.opener = fs.open
+		logger.Store(l)
+		done := make(chan struct{})
+		go func() {
+			l.start()
+			close(done)
+		}()
+
+		now := time.Now()
+		file, line := logWithCaller()
+		file = filepath.Base(filepath.Dir(file)) + "/caller_test.go"
+		Stop()
+		<-done
+
+		var entry map[string]interface{}
+		content := fs.file(l.filePathFor(now)).String()
+		if err := json.Unmarshal([]byte(strings.TrimSpace(content)), &entry); err != nil {
+			t.Fatalf("could not decode %q: %v", content, err)
+		}
+		if style == CallerStyleFields {
+			if entry["caller.file"] != file {
+				t.Errorf("unexpected caller.file %v", entry["caller.file"])
+			}
+			if entry["caller.line"] != float64(line) {
+				t.Errorf("expected caller.line %d, got %v", line, entry["caller.line"])
+			}
+			if entry["caller.func"] != "logWithCaller" {
+				t.Errorf("unexpected caller.func %v", entry["caller.func"])
+			}
+			if _, ok := entry["caller"]; ok {
+				t.Error("expected no combined caller field")
+			}
+			continue
+		}
+		if want := file + ":" + strconv.Itoa(line); entry["caller"] != want {
+			t.Errorf("expected caller %q, got %v", want, entry["caller"])
+		}
+	}
+}
+
+// TestShortFunc checks package paths are stripped from function symbols.
+func TestShortFunc(t *testing.T) {
+	for name, want := range map[string]string{
+		"github.com/acme/app.(*Server).Run": "(*Server).Run",
+		"main.main":                         "main",
+		"github.com/acme/app.run.func1":     "run.func1",
+	} {
+		if got := shortFunc(name); got != want {
+			t.Errorf("shortFunc(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+// chown_unix.go
+//
+// # Chronos Logging - File Ownership (Unix)
+//
+// Applies `Config.FileOwner` to newly created log files.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+
+//go:build !windows
+
+package chronos
+
+import "os"
+
+// chown sets the owner of path to the configured uid/gid.
+func chown(path string, owner *FileOwner) error {
+	return os.Chown(path, owner.UID, owner.GID)
+}
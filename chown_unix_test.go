@@ -0,0 +1,69 @@
+// chown_unix_test.go
+//
+// # Chronos Logging - File Ownership Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+
+//go:build !windows
+
+package chronos
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestFileOwnerApplied verifies a newly created log file is chowned to the
+// configured uid/gid. Changing ownership requires root.
+func TestFileOwnerApplied(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("chown requires root; skipping test")
+	}
+	Stop()
+
+	tempDir, err := os.MkdirTemp("", "chown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.FileOwner = &FileOwner{UID: 65534, GID: 65534}
+	var errs []error
+	cfg.ErrorHandler = func(err error) { errs = append(errs, err) }
+
+	l := newLogging(cfg, logLevels()[INFO])
+	logger.Store(l)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		l.start()
+	}()
+
+	Info("owned")
+	filename := l.filename(time.Now())
+	Stop()
+	wg.Wait()
+
+	if len(errs) != 0 {
+		t.Fatalf("unexpected writer errors: %v", errs)
+	}
+	info, err := os.Stat(filepath.Join(tempDir, filename))
+	if err != nil {
+		t.Fatalf("could not stat log file: %v", err)
+	}
+	st := info.Sys().(*syscall.Stat_t)
+	if st.Uid != 65534 || st.Gid != 65534 {
+		t.Errorf("expected owner 65534:65534, got %d:%d", st.Uid, st.Gid)
+	}
+}
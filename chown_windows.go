@@ -0,0 +1,21 @@
+// chown_windows.go
+//
+// # Chronos Logging - File Ownership (Windows)
+//
+// File ownership is not expressed as uid/gid on Windows, so `Config.FileOwner`
+// is ignored.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+
+//go:build windows
+
+package chronos
+
+// chown is a no-op on Windows.
+func chown(path string, owner *FileOwner) error {
+	return nil
+}
@@ -0,0 +1,469 @@
+// cloudwatch.go
+//
+// # Chronos Logging - CloudWatch Logs Sink
+//
+// Sends entries to an Amazon CloudWatch Logs stream with the PutLogEvents
+// API. Entries are batched on a dedicated goroutine within the API limits
+// (events and bytes per call, a 24 hour span, chronological order) and the
+// writer never blocks on the network: when the queue is full, entries are
+// dropped for this sink while file logging carries on as the local buffer.
+// Requests are signed with AWS Signature Version 4. A rejected sequence
+// token is refetched with DescribeLogStreams and the batch resent, and
+// other failures are retried according to `Config.RemoteBuffer`, like every
+// remote sink.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// CloudWatchConfig configures delivery of entries to CloudWatch Logs. The
+// log group and stream must already exist.
+type CloudWatchConfig struct {
+	// Region is the AWS region of the log group, e.g. "eu-west-2".
+	Region string `json:"region"`
+
+	// LogGroup and LogStream name the destination stream.
+	LogGroup  string `json:"log_group"`
+	LogStream string `json:"log_stream"`
+
+	// Endpoint overrides the service URL, which defaults to
+	// https://logs.<Region>.amazonaws.com, e.g. for a VPC endpoint.
+	Endpoint string `json:"endpoint"`
+
+	// AccessKeyID, SecretAccessKey and SessionToken sign the requests. When
+	// AccessKeyID is empty they are read from the AWS_ACCESS_KEY_ID,
+	// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+	AccessKeyID     string `json:"-"`
+	SecretAccessKey string `json:"-"`
+	SessionToken    string `json:"-"`
+
+	// BatchSize is the maximum number of entries sent per call. Defaults to,
+	// and may not exceed, the API limit of 10000.
+	BatchSize int `json:"batch_size"`
+
+	// FlushInterval is the longest an entry waits before a partial batch is
+	// sent. Defaults to five seconds.
+	FlushInterval time.Duration `json:"flush_interval"`
+
+	// QueueSize bounds the entries held for the sink, including those waiting
+	// to be retried. Defaults to 10000.
+	QueueSize int `json:"queue_size"`
+
+	// Client sends the requests. Defaults to http.DefaultClient.
+	Client *http.Client `json:"-"`
+}
+
+// PutLogEvents limits, see the CloudWatch Logs API reference.
+const (
+	cloudWatchMaxBatch     = 10000
+	cloudWatchMaxBytes     = 1048576
+	cloudWatchEventBytes   = 26
+	cloudWatchMaxEvent     = 262144
+	cloudWatchMaxSpan      = 24 * time.Hour
+	cloudWatchTokenRetries = 3
+)
+
+const (
+	cloudWatchTarget        = "Logs_20140328."
+	cloudWatchMaxBackoff    = 10 * time.Second
+	cloudWatchCloseTimeout  = 5 * time.Second
+	cloudWatchDefaultQueue  = 10000
+	cloudWatchDefaultPeriod = 5 * time.Second
+)
+
+// cloudWatchEvent is an InputLogEvent: the entry's time in milliseconds and
+// its rendered line.
+type cloudWatchEvent struct {
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// size returns the bytes the event counts against the batch limit.
+func (e cloudWatchEvent) size() int {
+	return len(e.Message) + cloudWatchEventBytes
+}
+
+// cloudWatchError is an error response from the CloudWatch Logs API.
+type cloudWatchError struct {
+	Type    string
+	Message string
+}
+
+func (e *cloudWatchError) Error() string {
+	return e.Type + ": " + e.Message
+}
+
+// cloudWatchSink batches entries and sends them to the log stream.
+type cloudWatchSink struct {
+	cfg     CloudWatchConfig
+	retry   RemoteBuffer
+	timeout time.Duration
+	entries chan cloudWatchEvent
+	done    chan struct{}
+	onError func(error)
+	dropped *atomic.Uint64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Owned by run().
+	token   string
+	failing bool
+}
+
+// newCloudWatchSink checks the credentials and starts the sending goroutine.
+// A call taking longer than timeout (if positive) is cancelled and fails.
+// Batches that exhaust their retries are dropped and added to dropped.
+func newCloudWatchSink(cfg CloudWatchConfig, retry RemoteBuffer, timeout time.Duration, onError func(error), dropped *atomic.Uint64) (*cloudWatchSink, error) {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = cloudWatchMaxBatch
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = cloudWatchDefaultPeriod
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = cloudWatchDefaultQueue
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://logs." + cfg.Region + ".amazonaws.com"
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.AccessKeyID == "" {
+		cfg.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		cfg.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("CloudWatch sink %s: no AWS credentials configured", cfg.LogGroup)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &cloudWatchSink{
+		cfg:     cfg,
+		retry:   retry.withDefaults(),
+		timeout: timeout,
+		entries: make(chan cloudWatchEvent, cfg.QueueSize),
+		done:    make(chan struct{}),
+		onError: onError,
+		dropped: dropped,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	go s.run()
+	return s, nil
+}
+
+// send queues the entry, rendered as line, without blocking. It reports
+// false if the entry was dropped because the queue is full. Lines too large
+// for a single event are truncated.
+func (s *cloudWatchSink) send(log Log, line string) bool {
+	msg := strings.TrimRight(line, "\r\n")
+	if max := cloudWatchMaxEvent - cloudWatchEventBytes; len(msg) > max {
+		msg = msg[:max]
+	}
+	select {
+	case s.entries <- cloudWatchEvent{Timestamp: log.TimeStamp.UnixMilli(), Message: msg}:
+		return true
+	default:
+		return false
+	}
+}
+
+// close sends queued entries, giving CloudWatch a bounded amount of time to
+// accept them.
+func (s *cloudWatchSink) close() {
+	close(s.entries)
+	select {
+	case <-s.done:
+	case <-time.After(cloudWatchCloseTimeout):
+		s.cancel()
+		<-s.done
+	}
+	s.cancel()
+}
+
+// run batches queued entries and sends them, retrying with backoff.
+func (s *cloudWatchSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []cloudWatchEvent
+	backoff := s.retry.Backoff
+	attempts := 0
+	var retry <-chan time.Time
+
+	for {
+		select {
+		case ev, ok := <-s.entries:
+			if !ok {
+				sortEvents(batch)
+				for len(batch) > 0 {
+					n := s.nextBatch(batch)
+					s.deliver(batch[:n])
+					batch = batch[n:]
+				}
+				return
+			}
+			if len(batch) >= s.cfg.QueueSize {
+				batch = batch[1:]
+				s.dropped.Add(1)
+			}
+			batch = append(batch, ev)
+			if retry != nil || len(batch) < s.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if retry != nil || len(batch) == 0 {
+				continue
+			}
+		case <-retry:
+			retry = nil
+		case <-s.ctx.Done():
+			return
+		}
+
+		sortEvents(batch)
+		for len(batch) > 0 {
+			n := s.nextBatch(batch)
+			if err := s.deliver(batch[:n]); err != nil {
+				attempts++
+				if attempts <= *s.retry.MaxRetries {
+					retry = time.After(backoff)
+					backoff = min(backoff*2, cloudWatchMaxBackoff)
+					break
+				}
+				s.dropped.Add(uint64(n))
+				s.onError(fmt.Errorf("CloudWatch sink %s dropped %d entries after %d retries: %w", s.cfg.LogGroup, n, *s.retry.MaxRetries, err))
+			}
+			batch = batch[n:]
+			backoff = s.retry.Backoff
+			attempts = 0
+		}
+		if len(batch) == 0 {
+			batch = nil
+		}
+	}
+}
+
+// sortEvents puts events in the chronological order PutLogEvents requires,
+// keeping the order of entries logged in the same millisecond.
+func sortEvents(events []cloudWatchEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp < events[j].Timestamp
+	})
+}
+
+// nextBatch returns how many of the sorted events fit in one PutLogEvents
+// call: at most BatchSize events and cloudWatchMaxBytes, spanning no more
+// than cloudWatchMaxSpan.
+func (s *cloudWatchSink) nextBatch(events []cloudWatchEvent) int {
+	size := 0
+	for i, ev := range events {
+		if i == s.cfg.BatchSize || size+ev.size() > cloudWatchMaxBytes ||
+			ev.Timestamp-events[0].Timestamp > cloudWatchMaxSpan.Milliseconds() {
+			return i
+		}
+		size += ev.size()
+	}
+	return len(events)
+}
+
+// deliver sends one batch with the current sequence token. When the token
+// is rejected it is refetched and the batch resent.
+func (s *cloudWatchSink) deliver(events []cloudWatchEvent) error {
+	for attempt := 0; ; attempt++ {
+		err := s.put(events)
+		var apiErr *cloudWatchError
+		if err != nil && errors.As(err, &apiErr) && attempt < cloudWatchTokenRetries {
+			switch apiErr.Type {
+			case "InvalidSequenceTokenException":
+				if err = s.refreshToken(); err == nil {
+					continue
+				}
+			case "DataAlreadyAcceptedException":
+				// An earlier attempt was stored after all.
+				err = s.refreshToken()
+			}
+		}
+		if err != nil {
+			s.fail(err)
+			return err
+		}
+		s.failing = false
+		return nil
+	}
+}
+
+// put calls PutLogEvents and records the next sequence token.
+func (s *cloudWatchSink) put(events []cloudWatchEvent) error {
+	in := struct {
+		LogGroupName  string            `json:"logGroupName"`
+		LogStreamName string            `json:"logStreamName"`
+		LogEvents     []cloudWatchEvent `json:"logEvents"`
+		SequenceToken string            `json:"sequenceToken,omitempty"`
+	}{s.cfg.LogGroup, s.cfg.LogStream, events, s.token}
+	var out struct {
+		NextSequenceToken string `json:"nextSequenceToken"`
+	}
+	if err := s.call("PutLogEvents", in, &out); err != nil {
+		return err
+	}
+	s.token = out.NextSequenceToken
+	return nil
+}
+
+// refreshToken fetches the stream's current sequence token with
+// DescribeLogStreams.
+func (s *cloudWatchSink) refreshToken() error {
+	in := struct {
+		LogGroupName        string `json:"logGroupName"`
+		LogStreamNamePrefix string `json:"logStreamNamePrefix"`
+	}{s.cfg.LogGroup, s.cfg.LogStream}
+	var out struct {
+		LogStreams []struct {
+			LogStreamName       string `json:"logStreamName"`
+			UploadSequenceToken string `json:"uploadSequenceToken"`
+		} `json:"logStreams"`
+	}
+	if err := s.call("DescribeLogStreams", in, &out); err != nil {
+		return err
+	}
+	for _, stream := range out.LogStreams {
+		if stream.LogStreamName == s.cfg.LogStream {
+			s.token = stream.UploadSequenceToken
+			return nil
+		}
+	}
+	return fmt.Errorf("log stream %s not found in %s", s.cfg.LogStream, s.cfg.LogGroup)
+}
+
+// call sends a signed request for action with the JSON body in and decodes
+// the response into out. API errors are returned as *cloudWatchError.
+func (s *cloudWatchSink) call(action string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	ctx := s.ctx
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", cloudWatchTarget+action)
+	s.sign(req, body, clock())
+
+	resp, err := s.cfg.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) != nil || apiErr.Type == "" {
+			return fmt.Errorf("%s failed: %s", action, resp.Status)
+		}
+		// Types may be qualified, e.g. "com.amazonaws.logs#...".
+		kind := apiErr.Type[strings.LastIndexByte(apiErr.Type, '#')+1:]
+		return &cloudWatchError{Type: kind, Message: apiErr.Message}
+	}
+	return json.Unmarshal(data, out)
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req.
+func (s *cloudWatchSink) sign(req *http.Request, body []byte, now time.Time) {
+	stamp := now.UTC().Format("20060102T150405Z")
+	date := stamp[:8]
+	req.Header.Set("X-Amz-Date", stamp)
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+
+	names := []string{"content-type", "host", "x-amz-date", "x-amz-target"}
+	if s.cfg.SessionToken != "" {
+		names = append(names, "x-amz-security-token")
+		sort.Strings(names)
+	}
+	var headers strings.Builder
+	for _, name := range names {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		headers.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signed := strings.Join(names, ";")
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonical := strings.Join([]string{
+		req.Method, path, req.URL.RawQuery, headers.String(), signed, sha256Hex(body),
+	}, "\n")
+
+	scope := date + "/" + s.cfg.Region + "/logs/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretAccessKey), date)
+	for _, part := range []string{s.cfg.Region, "logs", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.cfg.AccessKeyID, scope, signed, hex.EncodeToString(hmacSHA256(key, toSign))))
+}
+
+// fail reports the first error of a run of failures.
+func (s *cloudWatchSink) fail(err error) {
+	if s.failing {
+		return
+	}
+	s.failing = true
+	s.onError(fmt.Errorf("CloudWatch sink %s: %w", s.cfg.LogGroup, err))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
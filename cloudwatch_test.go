@@ -0,0 +1,165 @@
+// cloudwatch_test.go
+//
+// # Chronos Logging - CloudWatch Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockCloudWatch serves PutLogEvents and DescribeLogStreams for one stream,
+// enforcing sequence tokens the way CloudWatch Logs does.
+type mockCloudWatch struct {
+	mu      sync.Mutex
+	token   int
+	puts    [][]cloudWatchEvent
+	rejects int
+	authErr string
+}
+
+func (m *mockCloudWatch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") {
+		m.authErr = auth
+	}
+	var in struct {
+		LogGroupName        string
+		LogStreamName       string
+		LogStreamNamePrefix string
+		SequenceToken       string
+		LogEvents           []cloudWatchEvent
+	}
+	json.NewDecoder(r.Body).Decode(&in)
+	w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+
+	switch r.Header.Get("X-Amz-Target") {
+	case "Logs_20140328.DescribeLogStreams":
+		fmt.Fprintf(w, `{"logStreams":[{"logStreamName":"other","uploadSequenceToken":"x"},{"logStreamName":%q,"uploadSequenceToken":"token-%d"}]}`, in.LogStreamNamePrefix, m.token)
+	case "Logs_20140328.PutLogEvents":
+		if in.SequenceToken != fmt.Sprintf("token-%d", m.token) {
+			m.rejects++
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"__type":"InvalidSequenceTokenException","message":"The given sequenceToken is invalid."}`)
+			return
+		}
+		m.puts = append(m.puts, in.LogEvents)
+		m.token++
+		fmt.Fprintf(w, `{"nextSequenceToken":"token-%d"}`, m.token)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// TestCloudWatchSink sends entries to a mock CloudWatch endpoint whose
+// stream already has a sequence token, and asserts they arrive in batches
+// of at most BatchSize, in order, after the token is refetched once.
+func TestCloudWatchSink(t *testing.T) {
+	mock := &mockCloudWatch{token: 5}
+	srv := httptest.NewServer(mock)
+	defer srv.Close()
+
+	Stop()
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.Preset = PresetCloudWatch
+	cfg.SyncForTest = true
+	cfg.CloudWatch = &CloudWatchConfig{
+		Region:          "eu-west-2",
+		LogGroup:        "app",
+		LogStream:       "host-1",
+		Endpoint:        srv.URL,
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		BatchSize:       10,
+		FlushInterval:   time.Hour,
+	}
+	var errs []error
+	var errMu sync.Mutex
+	cfg.ErrorHandler = func(err error) {
+		errMu.Lock()
+		errs = append(errs, err)
+		errMu.Unlock()
+	}
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	const n = 25
+	for i := 0; i < n; i++ {
+		Infof("entry %d", i)
+	}
+	Stop()
+
+	mock.mu.Lock()
+	defer mock.mu.Unlock()
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if mock.authErr != "" {
+		t.Errorf("request not signed with the configured key: %q", mock.authErr)
+	}
+	if mock.rejects != 1 {
+		t.Errorf("expected one rejected token before refetching, got %d", mock.rejects)
+	}
+	if len(mock.puts) != 3 {
+		t.Fatalf("expected 3 PutLogEvents calls, got %d", len(mock.puts))
+	}
+	i := 0
+	for _, batch := range mock.puts {
+		if len(batch) > 10 {
+			t.Errorf("batch of %d exceeds BatchSize", len(batch))
+		}
+		for j, ev := range batch {
+			var got map[string]interface{}
+			if err := json.Unmarshal([]byte(ev.Message), &got); err != nil {
+				t.Fatalf("event is not JSON: %q", ev.Message)
+			}
+			if want := fmt.Sprintf("entry %d", i); got["message"] != want {
+				t.Errorf("event %d: expected %q, got %q", i, want, ev.Message)
+			}
+			if j > 0 && ev.Timestamp < batch[j-1].Timestamp {
+				t.Errorf("batch not in chronological order at event %d", i)
+			}
+			i++
+		}
+	}
+	if i != n {
+		t.Errorf("expected %d events, got %d", n, i)
+	}
+	if mock.token != 5+len(mock.puts) {
+		t.Errorf("expected each call to advance the token, got token-%d", mock.token)
+	}
+}
+
+// TestCloudWatchBatchLimits asserts batches are cut at the byte limit and
+// the 24 hour span.
+func TestCloudWatchBatchLimits(t *testing.T) {
+	s := &cloudWatchSink{cfg: CloudWatchConfig{BatchSize: cloudWatchMaxBatch}}
+	big := strings.Repeat("x", 200000)
+	events := make([]cloudWatchEvent, 8)
+	for i := range events {
+		events[i] = cloudWatchEvent{Timestamp: int64(i), Message: big}
+	}
+	if got, want := s.nextBatch(events), cloudWatchMaxBytes/(len(big)+cloudWatchEventBytes); got != want {
+		t.Errorf("expected %d events under the byte limit, got %d", want, got)
+	}
+
+	day := (24 * time.Hour).Milliseconds()
+	events = []cloudWatchEvent{{Timestamp: 0}, {Timestamp: day}, {Timestamp: day + 1}}
+	if got := s.nextBatch(events); got != 2 {
+		t.Errorf("expected the batch to stop at 24 hours, got %d events", got)
+	}
+}
@@ -0,0 +1,95 @@
+// compress.go
+//
+// # Chronos Logging - Compressing Rotated Files
+//
+// With `Config.Compress`, a log file is gzipped in the background once the
+// writer rotates away from it: the data is written to <name>.gz and the
+// original removed. Files are only compressed after the writer has closed
+// them for good, never while entries may still be appended.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+)
+
+// compressedExt is appended to the name of a compressed log file.
+const compressedExt = ".gz"
+
+// validCompressLevel reports whether level is accepted for
+// `Config.CompressLevel`: zero for the default, or a gzip level.
+func validCompressLevel(level int) bool {
+	return level == 0 || (level >= gzip.HuffmanOnly && level <= gzip.BestCompression)
+}
+
+// compressLevel returns the gzip level to use, see `Config.CompressLevel`.
+func (l *Logging) compressLevel() int {
+	if l.config.CompressLevel == 0 {
+		return gzip.DefaultCompression
+	}
+	return l.config.CompressLevel
+}
+
+// compressRotated compresses the rotated file at path in the background.
+// With `Config.CompressConcurrency`, the job waits for a free slot, so a
+// burst of rotations queues rather than compressing every file at once. The
+// writer waits for outstanding jobs before it exits.
+func (l *Logging) compressRotated(path string) {
+	l.compressing.Add(1)
+	go func() {
+		defer l.compressing.Done()
+		if l.compressSlots != nil {
+			l.compressSlots <- struct{}{}
+			defer func() { <-l.compressSlots }()
+		}
+		if err := l.compressor(path, l.compressLevel()); err != nil {
+			l.reportError(err)
+		}
+	}()
+}
+
+// compressFile writes path gzipped at level to path.gz and removes path. The
+// output is written under a temporary name first, so a partial .gz is never
+// left behind.
+func compressFile(path string, level int) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("could not open log file %s for compression: %w", path, err)
+	}
+	defer in.Close()
+
+	tmp := path + compressedExt + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("could not create compressed log file %s: %w", tmp, err)
+	}
+	gz, err := gzip.NewWriterLevel(out, level)
+	if err == nil {
+		if _, err = io.Copy(gz, in); err == nil {
+			err = gz.Close()
+		}
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path+compressedExt)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("could not compress log file %s: %w", path, err)
+	}
+	in.Close()
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("could not remove compressed log file %s: %w", path, err)
+	}
+	return nil
+}
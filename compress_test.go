@@ -0,0 +1,247 @@
+// compress_test.go
+//
+// # Chronos Logging - Compression Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// readGzip returns the decompressed contents of the file at path.
+func readGzip(t *testing.T, path string) string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// TestCompressLevels compresses the same content at the fastest and
+// smallest levels and asserts both round-trip and the higher level is not
+// larger.
+func TestCompressLevels(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 2000; i++ {
+		sb.WriteString("10:00:00\tINFO\tGET /api/orders 200\tduration=12ms user=alice\n")
+	}
+	content := sb.String()
+
+	sizes := map[int]int64{}
+	for _, level := range []int{gzip.BestSpeed, gzip.BestCompression} {
+		path := filepath.Join(t.TempDir(), "nexus_2025-01-01.log")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := compressFile(path, level); err != nil {
+			t.Fatalf("level %d: %v", level, err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("level %d: expected the original to be removed", level)
+		}
+		if got := readGzip(t, path+compressedExt); got != content {
+			t.Errorf("level %d: decompressed content differs", level)
+		}
+		info, err := os.Stat(path + compressedExt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizes[level] = info.Size()
+	}
+	if sizes[gzip.BestCompression] > sizes[gzip.BestSpeed] {
+		t.Errorf("expected level 9 (%d bytes) not to be larger than level 1 (%d bytes)", sizes[gzip.BestCompression], sizes[gzip.BestSpeed])
+	}
+
+	cfg := getConfig()
+	cfg.CompressLevel = 10
+	if err := Init(cfg); err == nil {
+		Stop()
+		t.Error("expected an out of range level to be rejected")
+	}
+}
+
+// TestCompressOnRotation asserts the previous period's file is gzipped once
+// the writer rotates and the current file is left alone.
+func TestCompressOnRotation(t *testing.T) {
+	Stop()
+	c := &fakeClock{t: time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)}
+	defer useClock(c)()
+
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.Compress = true
+	cfg.CompressLevel = gzip.BestSpeed
+	l := newLogging(cfg, logLevels()[INFO])
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	first := l.filePathFor(c.Now())
+	Info("first hour")
+	c.Advance(time.Hour)
+	second := l.filePathFor(c.Now())
+	Info("second hour")
+	Stop()
+	<-done
+
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be replaced by its .gz", first)
+	}
+	if got := readGzip(t, first+compressedExt); !strings.HasSuffix(got, "\tfirst hour\n") {
+		t.Errorf("unexpected compressed content %q", got)
+	}
+	if _, err := os.Stat(second); err != nil {
+		t.Errorf("expected the current file to stay uncompressed: %v", err)
+	}
+}
+
+// TestCompressConcurrency rotates several level files at once with a
+// compressor that reports how many jobs overlap, and asserts no more than
+// CompressConcurrency run together while every file is still compressed.
+func TestCompressConcurrency(t *testing.T) {
+	Stop()
+	c := &fakeClock{t: time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)}
+	defer useClock(c)()
+
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.Compress = true
+	cfg.CompressConcurrency = 2
+	cfg.SeparateByLevel = true
+	cfg.CombinedFile = true
+	l := newLogging(cfg, logLevels()[INFO])
+
+	var mu sync.Mutex
+	var running, peak int
+	var compressed []string
+	l.compressor = func(path string, level int) error {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+		time.Sleep(30 * time.Millisecond)
+		mu.Lock()
+		running--
+		compressed = append(compressed, path)
+		mu.Unlock()
+		return nil
+	}
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	levels := []string{INFO, WARN, ERROR, FATAL}
+	for _, level := range levels {
+		logger.Load().addLog(Log{TimeStamp: clock(), Level: level, Message: "first hour"})
+	}
+	c.Advance(time.Hour)
+	for _, level := range levels {
+		logger.Load().addLog(Log{TimeStamp: clock(), Level: level, Message: "second hour"})
+	}
+	Stop()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(compressed) != len(levels)+1 {
+		t.Errorf("expected %d rotated files compressed, got %d: %v", len(levels)+1, len(compressed), compressed)
+	}
+	if peak > cfg.CompressConcurrency {
+		t.Errorf("expected at most %d concurrent compressions, got %d", cfg.CompressConcurrency, peak)
+	}
+	if peak < cfg.CompressConcurrency {
+		t.Errorf("expected compressions to overlap up to %d, peaked at %d", cfg.CompressConcurrency, peak)
+	}
+}
+
+// TestCompressAfterIdleClose closes the file as IdleTimeout would before the
+// period ends and asserts it is still compressed once the writer rotates.
+func TestCompressAfterIdleClose(t *testing.T) {
+	Stop()
+	c := &fakeClock{t: time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)}
+	defer useClock(c)()
+
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.Compress = true
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	l := logger.Load()
+	first := l.filePathFor(c.Now())
+	Info("first hour")
+	l.inlineMu.Lock()
+	l.closeFiles()
+	l.inlineMu.Unlock()
+	c.Advance(time.Hour)
+	Info("second hour")
+	Stop()
+
+	if got := readGzip(t, first+compressedExt); !strings.HasSuffix(got, "\tfirst hour\n") {
+		t.Errorf("unexpected compressed content %q", got)
+	}
+}
+
+// TestCompressLeftover leaves the previous period's file uncompressed, as a
+// run that stopped before rotating would, and asserts the next run
+// compresses it.
+func TestCompressLeftover(t *testing.T) {
+	Stop()
+	c := &fakeClock{t: time.Date(2025, 1, 1, 10, 0, 0, 0, time.Local)}
+	defer useClock(c)()
+
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.Compress = true
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	first := PathFor(c.Now())
+	Info("earlier run")
+	Stop()
+
+	c.Advance(time.Hour)
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	second := PathFor(c.Now())
+	Info("this run")
+	Stop()
+
+	if got := readGzip(t, first+compressedExt); !strings.HasSuffix(got, "\tearlier run\n") {
+		t.Errorf("unexpected compressed content %q", got)
+	}
+	if _, err := os.Stat(second); err != nil {
+		t.Errorf("expected the current file to stay uncompressed: %v", err)
+	}
+}
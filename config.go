@@ -12,6 +12,12 @@
  // rotation cadence, and default verbosity.
  package chronos
 
+ import (
+     "encoding/json"
+     "io"
+     "time"
+ )
+
  // Config describes how the Chronos logger should operate.
  //
  // Typical usage:
@@ -38,9 +44,26 @@
      // Location is the absolute directory path where log files are written.
      // If left empty, Chronos chooses a platform-specific default derived from
      // AppName. The directory will be created with 0755 permissions if it does
-     // not exist.
+     // not exist. A relative path is resolved against the working directory
+     // at Init, and Init stores the absolute path back here. Symlinks in the
+     // path are resolved at Init and the logger works on the real directory
+     // (see PathFor); a dangling symlink makes Init fail.
      Location string `json:"location"`
 
+     // FallbackLocation, when true and Location is left empty, makes Init
+     // check that the OS default is writable and otherwise log to a
+     // user-writable directory instead ($XDG_STATE_HOME/<AppName>/logs,
+     // ~/.local/state/<AppName>/logs, or a temporary directory), with a WARN
+     // naming both. Without it an unwritable default fails every write.
+     FallbackLocation bool `json:"fallback_location"`
+
+     // Locations, when set, spreads log files across several directories,
+     // for example on different disks: each rotation period's files go to
+     // the next directory in turn. The directory is derived from the period
+     // (see PathFor), so readers can locate any file. Location still holds
+     // retention class directories.
+     Locations []string `json:"locations,omitempty"`
+
      // FilePeriod controls the log file rotation cadence by determining the
      // timestamp granularity embedded in the filename. Supported values are
      // LogPeriodHour, LogPeriodDay, LogPeriodWeek, LogPeriodMonth, and
@@ -49,13 +72,461 @@
 
      // Level is the minimum log severity that will be emitted. Messages below
      // this level are filtered before being printed or enqueued for file
-     // persistence. Valid values are DEBUG, INFO, WARN, ERROR, and FATAL.
+     // persistence. Valid values are DEBUG, INFO, WARN, ERROR, and FATAL,
+     // matched case-insensitively, plus the aliases WARNING, ERR, CRITICAL,
+     // and CRIT. Init normalizes it to the canonical name. In JSON it may
+     // also be given as a numeric severity (see Level).
      Level string `json:"level"`
 
      // AutoStop, when true, installs an OS signal handler (e.g., SIGINT/Ctrl-C
      // and SIGTERM) to automatically invoke Stop() so the logger flushes and
      // closes gracefully during application shutdown. Default is false to avoid
      // interfering with host application's own signal handling. Enable this if
-     // you do not already manage Stop() explicitly.
+     // you do not already manage Stop() explicitly. It can be combined with an
+     // application handler that calls Stop itself: Stop is idempotent, and
+     // whichever runs first stops the logger. The AutoStop handler belongs to
+     // the logger Init created and is removed when that logger stops, so it
+     // never stops a logger from a later Init.
      AutoStop bool `json:"auto_stop"`
+
+     // ReplayPreInit, when true, logs the entries captured before the first
+     // Init (the most recent 256, see CapturePreInit) through this logger
+     // once it starts, filtered by Level and with their original timestamps.
+     // Otherwise Init discards them.
+     ReplayPreInit bool `json:"replay_pre_init"`
+
+     // FileOwner, when set, is applied with os.Chown to each log file the
+     // writer creates, so services that drop privileges after start keep
+     // ownership of their logs. It is ignored on Windows.
+     FileOwner *FileOwner `json:"file_owner,omitempty"`
+
+     // FS, when set, opens log files in place of the operating system, for
+     // example an in-memory filesystem in tests or special storage. Chronos
+     // then creates no directories itself. Tail reads files through it when
+     // the files it opens with os.O_RDONLY implement io.Reader. Features that
+     // inspect or rewrite files on disk (FileOwner, retention, Compress,
+     // AuditChain and Snapshot) still use the OS. Default is nil (the OS).
+     FS FileSystem `json:"-"`
+
+     // ErrorHandler receives I/O errors raised by the background writer (for
+     // example a failed open or chown). If nil, errors are printed to stderr.
+     ErrorHandler func(error) `json:"-"`
+
+     // OnDrain, when set, is called by the writer goroutine each time it
+     // empties the queue, i.e. once after a burst has been written rather
+     // than after every entry. It must not block or log.
+     OnDrain func() `json:"-"`
+
+     // OnFileCreate, when set, is called with the path of each log file the
+     // writer opens for the first time, including the file opened at start,
+     // so log shippers can begin tailing it. It fires once per file, on its
+     // own goroutine so the writer is not held up; calls may overlap.
+     OnFileCreate func(path string) `json:"-"`
+
+     // ColorScope controls how much of each console line is wrapped in the
+     // level's color: the whole line (ColorScopeLine, the default) or only the
+     // level token (ColorScopeLevel). No colors are written at all when the
+     // NO_COLOR environment variable is set at Init.
+     ColorScope ColorScope `json:"color_scope"`
+
+     // IdleTimeout, when positive, closes the open log file after this long
+     // without writes. The file is reopened lazily on the next entry, so a
+     // quiet process does not hold an old rotation file open indefinitely.
+     IdleTimeout time.Duration `json:"idle_timeout"`
+
+     // Tee, when set, receives a copy of every formatted line written to the
+     // log file. Writes are best-effort: they happen on a separate goroutine
+     // and lines are dropped if the tee falls too far behind, so a slow tee
+     // never blocks file logging.
+     Tee io.Writer `json:"-"`
+
+     // FieldOrder lists structured field keys that are rendered first, in the
+     // given order. Remaining keys follow, sorted alphabetically. This keeps
+     // columns stable for fixed-position parsing tools.
+     FieldOrder []string `json:"field_order"`
+
+     // MaxFields, when positive, caps the fields kept on each entry. The
+     // first MaxFields in rendering order (FieldOrder keys, then sorted) are
+     // kept and a fields_truncated field counts the rest.
+     MaxFields int `json:"max_fields"`
+
+     // QuietUntilError, when true, holds entries below ERROR in a bounded
+     // in-memory buffer instead of emitting them. The first ERROR (or more
+     // severe) entry flushes the buffer ahead of itself and switches the
+     // logger to normal streaming. If no error occurs the buffered entries are
+     // discarded, so successful runs stay silent.
+     QuietUntilError bool `json:"quiet_until_error"`
+
+     // PauseBufferSize bounds the entries held in memory between Pause and
+     // Resume (default 10000). Entries beyond it are dropped and reported by
+     // a WARN on Resume.
+     PauseBufferSize int `json:"pause_buffer_size"`
+
+     // PauseDrop, when true, discards entries logged while paused instead
+     // of writing them on Resume.
+     PauseDrop bool `json:"pause_drop"`
+
+     // Compress, when true, gzips each log file in the background once the
+     // writer has rotated to the next one, replacing name.log with
+     // name.log.gz. This includes a file closed by IdleTimeout before the
+     // rotation, and the previous period's file left uncompressed by an
+     // earlier run.
+     Compress bool `json:"compress"`
+
+     // CompressLevel is the gzip level used by Compress, from 1 (fastest)
+     // to 9 (smallest), or gzip.HuffmanOnly (-2). Zero uses gzip's default.
+     CompressLevel int `json:"compress_level"`
+
+     // CompressConcurrency, when positive, limits how many rotated files are
+     // compressed at once; further files wait their turn. This keeps a burst
+     // of rotations from spiking CPU. Zero compresses every file at once.
+     CompressConcurrency int `json:"compress_concurrency"`
+
+     // Timezone names the IANA zone (e.g. "America/New_York") used for
+     // rotation boundaries, filenames, and the times rendered in output, so
+     // daily files follow that zone's days whatever the server's zone. It
+     // defaults to the local zone; "UTC" is accepted. Invalid names are
+     // rejected by Init.
+     Timezone string `json:"timezone"`
+
+     // FilenameTZSuffix, when true, appends the UTC offset of the file's
+     // period (e.g. +0000 or -0500) to the date part of filenames, so files
+     // from servers in different zones can be told apart by name. Where the
+     // zone observes daylight saving, the name changes with the offset.
+     FilenameTZSuffix bool `json:"filename_tz_suffix"`
+
+     // FilePrefix starts each log filename (<FilePrefix>_<date>.log).
+     // Defaults to "nexus". It must not contain path separators.
+     FilePrefix string `json:"file_prefix"`
+
+     // FileExtension ends each log filename. It defaults to the format's
+     // extension: .jsonl for FormatJSON, .csv for FormatCSV and .log
+     // otherwise. A missing leading dot is added. It must not contain path
+     // separators and does not apply to FilenameTemplate, which names the
+     // extension itself.
+     FileExtension string `json:"file_extension"`
+
+     // InstanceID, when set, is appended to each log filename
+     // (nexus_<date>_<InstanceID>.log) so multiple instances of the same app
+     // sharing a log directory write separate files instead of interleaving.
+     // It must not contain path separators.
+     InstanceID string `json:"instance_id"`
+
+     // ReopenOnSIGUSR1, when true, installs a SIGUSR1 handler that closes the
+     // open log file so the next entry reopens it at its original path. This
+     // cooperates with logrotate's rename-and-signal strategy. It is a no-op
+     // on Windows.
+     ReopenOnSIGUSR1 bool `json:"reopen_on_sigusr1"`
+
+     // FlushOnSIGUSR2, when true, installs a SIGUSR2 handler that writes out
+     // every entry logged so far, including output held by FlushInterval or
+     // FlushThreshold, without rotating or stopping, so an external copy of
+     // the file is complete. It is a no-op on Windows.
+     FlushOnSIGUSR2 bool `json:"flush_on_sigusr2"`
+
+     // IgnoreSIGPIPE, when true, handles SIGPIPE while the logger runs. Go
+     // normally exits when a write to a closed stdout or stderr pipe raises
+     // SIGPIPE (e.g. `app | head`); with this set the write fails with EPIPE
+     // instead, console output is disabled and file logging continues. This
+     // changes the signal's behavior for the whole process, including writes
+     // the application makes itself. It is a no-op on Windows.
+     IgnoreSIGPIPE bool `json:"ignore_sigpipe"`
+
+     // SeparateByLevel, when true, writes each entry to a file for its level,
+     // named nexus_<level>_<date>.log (e.g. nexus_error_2025-01-02.log).
+     SeparateByLevel bool `json:"separate_by_level"`
+
+     // CombinedFile, when SeparateByLevel is enabled, additionally writes every
+     // entry to the usual combined file. It has no effect otherwise.
+     CombinedFile bool `json:"combined_file"`
+
+     // CloudWatch, when set, additionally sends every entry, rendered as in
+     // the log files, to an Amazon CloudWatch Logs stream with PutLogEvents.
+     // Like the remote sinks it never blocks the writer and the files remain the
+     // local buffer. Pair it with PresetCloudWatch for JSON entries.
+     CloudWatch *CloudWatchConfig `json:"cloudwatch,omitempty"`
+
+     // RemoteSinks receive every entry after it is written to file. Failed
+     // sends are retried from a bounded queue configured by RemoteBuffer.
+     // Sinks implementing io.Closer are closed once the logger stops.
+     RemoteSinks []RemoteSink `json:"-"`
+
+     // Sinks are remote sinks with routing rules: each receives only the
+     // entries its Match accepts, e.g. ERROR and above with env=prod.
+     Sinks []SinkConfig `json:"sinks,omitempty"`
+
+     // CustomSinks receive each entry at or above their Level, alongside the
+     // log files, each from its own goroutine and queue (see Sink). They are
+     // flushed with the files and closed when the logger stops; entries that
+     // do not fit in a sink's queue are dropped and counted by SinkDropped.
+     CustomSinks []Sink `json:"-"`
+
+     // RemoteBuffer governs retries for all remote sinks (RemoteSinks,
+     // Sinks and CloudWatch): queue size, retry count, and initial
+     // backoff.
+     RemoteBuffer RemoteBuffer `json:"remote_buffer"`
+
+     // RemoteTimeout, when positive, bounds each send to a remote sink or
+     // CloudWatch. A send still running after the timeout has its context
+     // cancelled and counts as failed, so it is retried or dropped per
+     // RemoteBuffer.
+     RemoteTimeout time.Duration `json:"remote_timeout"`
+
+     // IncludeWriteTime, when true, stamps each entry with the time the writer
+     // persists it and renders it next to the call time, both with microsecond
+     // precision. The difference between the two is the queue latency.
+     IncludeWriteTime bool `json:"include_write_time"`
+
+     // IncludeSeverityNumber, when true, adds the level's numeric severity
+     // (its value in the level table, e.g. 20 for INFO) to each line: a
+     // column after the level in text, a numeric "severity" field in JSON.
+     IncludeSeverityNumber bool `json:"include_severity_number"`
+
+     // JSONKeys renames the standard keys of FormatJSON objects for the
+     // ingestion system reading them, e.g. {"msg": "message", "level":
+     // "severity"}. The keys that can be renamed are "time", "level", "msg"
+     // and "caller"; any not listed keep their default name.
+     JSONKeys map[string]string `json:"json_keys,omitempty"`
+
+     // Preset applies the settings an ingestion platform expects. PresetGCP
+     // selects FormatJSON with Google Cloud Logging's "timestamp", "severity"
+     // and "message" keys and severity names (WARNING for WARN, CRITICAL for
+     // FATAL, ALERT for PANIC and DEFAULT for registered levels).
+     // PresetCloudWatch selects FormatJSON with "timestamp" and
+     // "message" keys, which CloudWatch Logs Insights discovers as fields.
+     // Entries set in JSONKeys take precedence over the preset's.
+     Preset Preset `json:"preset"`
+
+     // FieldSeparator separates the columns (time, level, message, fields) of
+     // text lines in files and on the console. Defaults to a tab. It must not
+     // contain a line break.
+     FieldSeparator string `json:"field_separator"`
+
+     // EscapeNewlines, when true, writes line breaks in text messages as the
+     // two characters \n (and \r), so every entry stays on one line.
+     EscapeNewlines bool `json:"escape_newlines"`
+
+     // MultilineIndent, when set and EscapeNewlines is false, prefixes each
+     // continuation line of a multi-line text message (e.g. "    " or "  | ")
+     // so it reads as part of the entry rather than a separate line.
+     MultilineIndent string `json:"multiline_indent"`
+
+     // Format selects the file encoding: FormatText (default), FormatJSON
+     // (one object per line), FormatCSV (RFC 4180 rows under a header row
+     // written when a file is created), or FormatBinary, a compact
+     // length-prefixed encoding read back with DecodeFile. Console output is
+     // always text.
+     Format LogFormat `json:"format"`
+
+     // CSVColumns names the fields that get their own column with FormatCSV,
+     // in order, after the timestamp, level and message columns. It defaults
+     // to the keys of DefaultFields, sorted. Only these fields become
+     // columns: any other field is written as key=value pairs in a final
+     // "fields" column, since the header is fixed when the file is created.
+     CSVColumns []string `json:"csv_columns,omitempty"`
+
+     // AuditChain, when true, ends each line in text and JSON log files with
+     // a hash chaining it to the previous line, so any later edit can be
+     // detected with VerifyChain. It cannot be used with FormatBinary or
+     // FormatCSV.
+     AuditChain bool `json:"audit_chain"`
+
+     // FIFO is the path of a named pipe to write entries to instead of
+     // rotating files, for log shippers that read from a pipe. A named pipe
+     // at Location is used the same way. The pipe is opened without blocking:
+     // entries are dropped while no reader is connected. Unix only.
+     FIFO string `json:"fifo"`
+
+     // ConsoleBuffered, when true, buffers console output and flushes it
+     // whenever the writer catches up with the queue, and on Stop, saving a
+     // write per line. Lines may then lag under load and are lost if the
+     // process dies first. By default each line is flushed as it is logged.
+     ConsoleBuffered bool `json:"console_buffered"`
+
+     // GuardConsole, when true, writes and flushes each console line while
+     // holding ConsoleMutex, so log lines appear at once and never split or
+     // interleave with application output printed under the same mutex.
+     GuardConsole bool `json:"guard_console"`
+
+     // ConsoleWriter receives console output instead of os.Stdout, e.g. a
+     // buffer in tests or a pane in an embedding application.
+     ConsoleWriter io.Writer `json:"-"`
+
+     // ConsoleErrWriter, when set, receives the console lines of ERROR and
+     // more severe entries instead of ConsoleWriter, e.g. os.Stderr.
+     ConsoleErrWriter io.Writer `json:"-"`
+
+     // SyncForTest, when true, writes each entry to its file(s) before the
+     // logging call returns, with no writer goroutine or queue, so tests can
+     // assert on output immediately. Batching and buffered writes are
+     // disabled. Meant for tests, not production.
+     SyncForTest bool `json:"sync_for_test"`
+
+     // FilenameTemplate, when set, replaces the built-in filename scheme, e.g.
+     // "{app}-{level}-{date:2006/01/02}.log". Tokens are {app}, {level},
+     // {instance}, {date} (the FilePeriod date part), and {date:LAYOUT} with
+     // a Go time layout. Using {level} writes one file per level. Unknown
+     // tokens are rejected by Init.
+     FilenameTemplate string `json:"filename_template"`
+
+     // BufferSize is the capacity of the queue between callers and the
+     // writer. Defaults to 10000.
+     BufferSize int `json:"buffer_size"`
+
+     // MaxBlockDuration bounds how long a caller may block on a full queue.
+     // Once exceeded, entries are dropped and counted (see Dropped) instead
+     // of blocking, until the writer drains the queue; a WARN then reports
+     // how many were lost. Zero blocks indefinitely.
+     MaxBlockDuration time.Duration `json:"max_block_duration"`
+
+     // SmoothRate, when positive, caps file output at this many lines per
+     // second by spacing writes evenly; bursts wait in the queue rather than
+     // being dropped, which delays them by up to BufferSize / SmoothRate
+     // seconds. Console output is not paced.
+     SmoothRate int `json:"smooth_rate"`
+
+     // DropWarnings, when true, logs a WARN each time the total number of
+     // entries lost to overflow (see MaxBlockDuration) and sampling crosses
+     // the next power of ten (10, 100, 1000, ...), so silent data loss shows
+     // up in the logs themselves.
+     DropWarnings bool `json:"drop_warnings"`
+
+     // DropWarnStep, when set, enables the drop warnings with thresholds at
+     // every multiple of DropWarnStep instead of powers of ten.
+     DropWarnStep int `json:"drop_warn_step"`
+
+     // DefaultFields are added to every entry, e.g. service, version, and
+     // environment. Fields set on an entry override defaults with the same
+     // key. More can be added at runtime with AddDefaultField.
+     DefaultFields Fields `json:"default_fields,omitempty"`
+
+     // SanitizeUTF8 replaces invalid UTF-8 in messages and string field
+     // values with U+FFFD before entries are written anywhere. It is always
+     // on for the JSON format.
+     SanitizeUTF8 bool `json:"sanitize_utf8"`
+
+     // BatchWindow, when positive, lets the writer gather entries arriving
+     // within this window and write them to each file in one call. A lone
+     // entry is delayed by at most the window.
+     BatchWindow time.Duration `json:"batch_window"`
+
+     // BatchSize caps the entries gathered in one batch. Defaults to 100.
+     BatchSize int `json:"batch_size"`
+
+     // FlushInterval, when positive, holds file output in memory and writes
+     // it out at this interval instead of on every entry. Stop, Snapshot,
+     // and ErrorSync flush immediately.
+     FlushInterval time.Duration `json:"flush_interval"`
+
+     // FlushThreshold, when positive, buffers file output like FlushInterval
+     // and also writes it out as soon as this many entries have accumulated,
+     // bounding loss on a crash by count. With both set, whichever comes
+     // first triggers the flush.
+     FlushThreshold int `json:"flush_threshold"`
+
+     // Retention maps retention classes to the maximum age of their files.
+     // Entries logged with InfoRetention are written to a subdirectory of
+     // Location named after their class, and files there older than the
+     // class's maximum age are deleted when the writer starts and whenever
+     // it opens a file. Classes without a positive age are kept forever.
+     Retention map[string]time.Duration `json:"retention,omitempty"`
+
+     // IncludeModule, when true, adds a "module" field to each entry holding
+     // the import path of the package that logged it. It is much cheaper
+     // than full caller information and useful for filtering by subsystem.
+     IncludeModule bool `json:"include_module"`
+
+     // ModuleLevels sets a minimum level per component or module, overriding
+     // Level, e.g. {"db": "DEBUG"} with a global INFO. An entry is matched by
+     // its "component" field, or failing that its "module" field (see
+     // IncludeModule); other entries use Level.
+     ModuleLevels map[string]string `json:"module_levels,omitempty"`
+
+     // FieldFilters keeps only the entries whose fields satisfy every filter,
+     // e.g. {Key: "tenant", Op: FilterEquals, Value: "acme"}; the rest are
+     // dropped before reaching any output. Filters see the entry's own
+     // fields along with caller details and default fields.
+     FieldFilters []FieldFilter `json:"field_filters,omitempty"`
+
+     // IncludeCaller, when true, records the source location that logged each
+     // entry, rendered according to CallerStyle.
+     IncludeCaller bool `json:"include_caller"`
+
+     // CallerStyle selects the caller representation: CallerStyleString
+     // (default) adds a "caller" field such as "app/server.go:42", and
+     // CallerStyleFields adds separate "caller.file", "caller.line", and
+     // "caller.func" fields (the short function name) for indexing.
+     CallerStyle CallerStyle `json:"caller_style"`
+
+     // IncludeGoroutineID, when true, adds a "goroutine" field holding the ID
+     // of the goroutine that logged each entry, for debugging concurrency.
+     // Go does not expose the ID, so it is parsed from a stack trace taken
+     // on every call: this is costly, and best left off in production.
+     IncludeGoroutineID bool `json:"include_goroutine_id"`
+
+     // LineEnding terminates each line in text and JSON log files:
+     // LineEndingLF (default) or LineEndingCRLF. Console output keeps "\n".
+     LineEnding LineEnding `json:"line_ending"`
+
+     // Sampling, when set, thins out repeated messages: the first
+     // BurstAllowance occurrences of each level and message are logged
+     // unthrottled, then only every Thereafter-th one. Discarded entries are
+     // counted (see Sampled). ERROR and more severe levels are not sampled
+     // by this rule.
+     Sampling *SampleRule `json:"sampling,omitempty"`
+
+     // LevelSampling gives levels, by name, their own sampling rule in place
+     // of Sampling, e.g. aggressive sampling for INFO. It is the only way to
+     // sample ERROR and more severe levels.
+     LevelSampling map[string]SampleRule `json:"level_sampling,omitempty"`
+
+     // DedupWindow, when positive, suppresses an ERROR (or more severe)
+     // entry whose message was already logged within the window. The first
+     // occurrence after the window is logged with a "suppressed" field
+     // counting the duplicates held back.
+     DedupWindow time.Duration `json:"dedup_window"`
+
+     // HookWorkers, when positive, runs the handler registered with
+     // SetHandler on this many worker goroutines fed by a bounded queue,
+     // instead of inline on the logging goroutine. Calls that do not fit in
+     // the queue are dropped and counted (see HookDropped).
+     HookWorkers int `json:"hook_workers"`
+
+     // Heartbeat, when positive, logs a "heartbeat" entry at HeartbeatLevel
+     // this often from a background goroutine, so a quiet service shows it is
+     // alive and its current log file keeps being written. Zero disables it.
+     Heartbeat time.Duration `json:"heartbeat"`
+
+     // HeartbeatLevel is the level of heartbeat entries, DEBUG by default.
+     // Heartbeats below the logger's level are filtered like any entry.
+     HeartbeatLevel string `json:"heartbeat_level"`
+ }
+
+ // FileOwner identifies the numeric user and group that should own log files.
+ type FileOwner struct {
+     UID int `json:"uid"`
+     GID int `json:"gid"`
+ }
+
+ // UnmarshalJSON decodes a Config, accepting "level" as either a level name
+ // or a numeric severity. An empty level is left for Init to default.
+ func (c *Config) UnmarshalJSON(data []byte) error {
+     type plain Config
+     aux := struct {
+         *plain
+         Level json.RawMessage `json:"level"`
+     }{plain: (*plain)(c)}
+     if err := json.Unmarshal(data, &aux); err != nil {
+         return err
+     }
+     switch string(aux.Level) {
+     case "", "null", `""`:
+         return nil
+     }
+     var level Level
+     if err := json.Unmarshal(aux.Level, &level); err != nil {
+         return err
+     }
+     c.Level = level.String()
+     return nil
  }
@@ -0,0 +1,200 @@
+// console.go
+//
+// # Chronos Logging - Console Output
+//
+// Renders entries for the terminal, wrapping either the whole line or just
+// the level token in an ANSI color according to `Config.ColorScope`, and
+// writes them through a console writer that flushes each line unless
+// `Config.ConsoleBuffered` is set. Following the NO_COLOR
+// convention (https://no-color.org), no color codes are written when the
+// NO_COLOR environment variable is set, whatever its value.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// ColorScope selects which part of a console line is colorized.
+type ColorScope string
+
+// Supported color scopes.
+//
+// - ColorScopeLine  => the entire line is colored (default)
+// - ColorScopeLevel => only the level token is colored
+const (
+	ColorScopeLine  ColorScope = "line"
+	ColorScopeLevel ColorScope = "level"
+)
+
+// Color codes for terminal output
+const (
+	colorRed     = "\033[31m"
+	colorGreen   = "\033[32m"
+	colorYellow  = "\033[33m"
+	colorBlue    = "\033[34m"
+	colorPurple  = "\033[35m"
+	colorBoldRed = "\033[1;31m"
+	colorReset   = "\033[0m"
+)
+
+// levelColor returns the ANSI color used for the given level.
+func levelColor(level string) string {
+	switch level {
+	case PANIC:
+		return colorBoldRed
+	case FATAL:
+		return colorPurple
+	case ERROR:
+		return colorRed
+	case WARN:
+		return colorYellow
+	case INFO:
+		return colorGreen
+	case DEBUG:
+		return colorBlue
+	default:
+		if color, ok := levelColors()[level]; ok && color != "" {
+			return color
+		}
+		return colorReset
+	}
+}
+
+// noColorEnv is the environment variable that disables console colors.
+const noColorEnv = "NO_COLOR"
+
+// formatConsole renders an entry for the console (without a trailing
+// newline), applying the configured color scope unless NO_COLOR was set.
+func (l *Logging) formatConsole(log Log) string {
+	b := getBuffer()
+	defer putBuffer(b)
+	*b = l.appendConsole(*b, log)
+	return string(*b)
+}
+
+// appendConsole appends the line formatConsole renders for log to b.
+func (l *Logging) appendConsole(b []byte, log Log) []byte {
+	if l.noColor {
+		return l.appendLine(b, log)
+	}
+	color := levelColor(log.Level)
+	if l.config.ColorScope == ColorScopeLevel {
+		log.Level = color + log.Level + colorReset
+		return l.appendLine(b, log)
+	}
+	b = append(b, color...)
+	b = l.appendLine(b, log)
+	return append(b, colorReset...)
+}
+
+// writeConsole writes log to the console.
+func (l *Logging) writeConsole(log Log) {
+	b := getBuffer()
+	*b = l.appendConsole(*b, log)
+	l.console.writeLine(log.Level, *b)
+	putBuffer(b)
+}
+
+// ConsoleMutex serializes console output with the application's own writes
+// to stdout when `Config.GuardConsole` is set. Hold it around prints that
+// must not interleave with log lines:
+//
+//	chronos.ConsoleMutex.Lock()
+//	fmt.Printf("progress: %d%%\n", pct)
+//	chronos.ConsoleMutex.Unlock()
+//
+// Do not log while holding it: the console writer takes it for each line,
+// so the call would deadlock.
+var ConsoleMutex sync.Mutex
+
+// consoleWriter writes console lines, flushing each one as it is written.
+// With buffered set (`Config.ConsoleBuffered`) lines are instead flushed by
+// the background writer once its queue drains, and by Stop. When errW is
+// set, ERROR and more severe lines go there instead of w
+// (`Config.ConsoleErrWriter`). After the first write error (for example
+// stdout is a pipe whose reader has gone, with `Config.IgnoreSIGPIPE` set)
+// the failure is reported once and console output is disabled for the rest
+// of the run; file logging is unaffected. An entry is written whole while holding mu, so
+// entries logged from concurrent goroutines, multi-line ones included, never
+// interleave with one another.
+type consoleWriter struct {
+	mu   sync.Mutex
+	w    *bufio.Writer
+	errW *bufio.Writer
+	// buffered holds lines back until flush is called.
+	buffered bool
+	// guard, when set, is held around each line, which is then flushed at
+	// once (`Config.GuardConsole`).
+	guard  sync.Locker
+	failed bool
+	report func(error)
+}
+
+// newConsoleWriter returns a console writer for out, holding lines back
+// until flush when buffered is set. Write errors are printed to stderr until
+// report is replaced.
+func newConsoleWriter(out io.Writer, buffered bool) *consoleWriter {
+	return &consoleWriter{
+		w:        bufio.NewWriter(out),
+		buffered: buffered,
+		report: func(err error) {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		},
+	}
+}
+
+// writeLine writes line, an entry at level, followed by a newline.
+func (c *consoleWriter) writeLine(level string, line []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failed {
+		return
+	}
+	w := c.w
+	if c.errW != nil && logLevels()[level] >= logLevels()[ERROR] {
+		w = c.errW
+	}
+	if c.guard != nil {
+		c.guard.Lock()
+		defer c.guard.Unlock()
+	}
+	w.Write(line)
+	err := w.WriteByte('\n')
+	if err == nil && (!c.buffered || c.guard != nil) {
+		err = w.Flush()
+	}
+	c.check(err)
+}
+
+// flush writes any buffered output.
+func (c *consoleWriter) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failed {
+		return
+	}
+	err := c.w.Flush()
+	if err == nil && c.errW != nil {
+		err = c.errW.Flush()
+	}
+	c.check(err)
+}
+
+// check disables the console after its first write error.
+func (c *consoleWriter) check(err error) {
+	if err == nil {
+		return
+	}
+	c.failed = true
+	c.report(fmt.Errorf("console output disabled after write error: %w", err))
+}
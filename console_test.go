@@ -0,0 +1,349 @@
+// console_test.go
+//
+// # Chronos Logging - Console Output Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestColorScopeLevel asserts that in "level" mode only the level token is
+// colored: the reset code follows the level immediately.
+func TestColorScopeLevel(t *testing.T) {
+	cfg := getConfig()
+	cfg.ColorScope = ColorScopeLevel
+	l := newLogging(cfg, logLevels()[INFO])
+
+	out := l.formatConsole(Log{TimeStamp: time.Now(), Level: WARN, Message: "careful"})
+	if !strings.Contains(out, colorYellow+WARN+colorReset+"\tcareful") {
+		t.Errorf("expected reset right after level token, got %q", out)
+	}
+	if strings.HasPrefix(out, colorYellow) {
+		t.Errorf("expected timestamp to be uncolored, got %q", out)
+	}
+}
+
+// TestColorScopeLine asserts the default mode wraps the whole line.
+func TestColorScopeLine(t *testing.T) {
+	l := newLogging(getConfig(), logLevels()[INFO])
+
+	out := l.formatConsole(Log{TimeStamp: time.Now(), Level: ERROR, Message: "boom"})
+	if !strings.HasPrefix(out, colorRed) || !strings.HasSuffix(out, "boom"+colorReset) {
+		t.Errorf("expected whole line colored, got %q", out)
+	}
+}
+
+// TestConsoleFlushedOnStop asserts console lines are written immediately by
+// default, and that ConsoleBuffered output is complete as soon as Stop
+// returns.
+func TestConsoleFlushedOnStop(t *testing.T) {
+	for _, buffered := range []bool{false, true} {
+		Stop()
+		out := &syncBuffer{}
+		cfg := getConfig()
+		cfg.ConsoleBuffered = buffered
+		l := newLogging(cfg, logLevels()[INFO])
+		l.opener = newMemFS().open
+		l.console = newConsoleWriter(out, buffered)
+		logger.Store(l)
+		done := make(chan struct{})
+		go func() {
+			l.start()
+			close(done)
+		}()
+
+		Info("first")
+		if !buffered && !strings.Contains(out.String(), "first") {
+			t.Errorf("expected the line written immediately, got %q", out.String())
+		}
+		Warn("second")
+		Stop()
+		if got := out.String(); !strings.Contains(got, "first") || !strings.HasSuffix(got, "second"+colorReset+"\n") {
+			t.Errorf("buffered=%v: expected both lines after Stop, got %q", buffered, got)
+		}
+		<-done
+	}
+}
+
+// TestConsoleClosedPipe points stdout at a pipe whose reader is closed and
+// asserts the failure is reported once while entries keep reaching the file.
+func TestConsoleClosedPipe(t *testing.T) {
+	Stop()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+	defer w.Close()
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	tempDir, err := os.MkdirTemp("", "console")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	var (
+		errMu  sync.Mutex
+		errors []error
+	)
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.ErrorHandler = func(err error) {
+		errMu.Lock()
+		defer errMu.Unlock()
+		errors = append(errors, err)
+	}
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	path := logger.Load().filePathFor(time.Now())
+	for i := 0; i < 3; i++ {
+		Infof("entry %d", i)
+	}
+	logger.Load().flush()
+	Stop()
+	os.Stdout = stdout
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read log file: %v", err)
+	}
+	if got := strings.Count(string(content), "\n"); got != 3 {
+		t.Errorf("expected 3 lines in file, got %d: %q", got, content)
+	}
+	errMu.Lock()
+	defer errMu.Unlock()
+	if len(errors) != 1 || !strings.Contains(errors[0].Error(), "console output disabled") {
+		t.Errorf("expected one console error, got %v", errors)
+	}
+}
+
+// TestConsoleWriters sets both console writers to buffers and asserts
+// entries below ERROR go to ConsoleWriter and the rest to ConsoleErrWriter.
+func TestConsoleWriters(t *testing.T) {
+	Stop()
+	out, errOut := &syncBuffer{}, &syncBuffer{}
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.ConsoleWriter = out
+	cfg.ConsoleErrWriter = errOut
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	Info("started")
+	Warn("slow")
+	Error("failed")
+	Stop()
+
+	if s := out.String(); !strings.Contains(s, "started") || !strings.Contains(s, "slow") || strings.Contains(s, "failed") {
+		t.Errorf("unexpected ConsoleWriter output %q", s)
+	}
+	if s := errOut.String(); !strings.Contains(s, "\tfailed") || strings.Contains(s, "started") {
+		t.Errorf("unexpected ConsoleErrWriter output %q", s)
+	}
+}
+
+// TestNoColor sets NO_COLOR and asserts console output, including a
+// level-scoped line, carries no escape sequences.
+func TestNoColor(t *testing.T) {
+	Stop()
+	t.Setenv(noColorEnv, "1")
+	for _, scope := range []ColorScope{ColorScopeLine, ColorScopeLevel} {
+		out := &syncBuffer{}
+		cfg := getConfig()
+		cfg.Location = t.TempDir()
+		cfg.ConsoleWriter = out
+		cfg.ColorScope = scope
+		cfg.SyncForTest = true
+		if err := Init(cfg); err != nil {
+			t.Fatalf("Init failed: %v", err)
+		}
+		Info("plain")
+		Error("still plain")
+		Stop()
+
+		s := out.String()
+		if !strings.Contains(s, "\tINFO\tplain\n") || strings.Contains(s, "\033") {
+			t.Errorf("%s: expected uncolored output, got %q", scope, s)
+		}
+	}
+}
+
+// TestPooledLinesConcurrent logs from many goroutines at once and asserts
+// every console line comes out whole, so no pooled buffer is shared.
+func TestPooledLinesConcurrent(t *testing.T) {
+	Stop()
+	t.Setenv(noColorEnv, "1")
+	out := &syncBuffer{}
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.ConsoleWriter = out
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	const workers, n = 8, 200
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				WithFields(Fields{"worker": w, "i": i}).Infof("message %d from %d", i, w)
+			}
+		}()
+	}
+	wg.Wait()
+	Stop()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != workers*n {
+		t.Fatalf("expected %d lines, got %d", workers*n, len(lines))
+	}
+	for _, line := range lines {
+		var i, w int
+		cols := strings.Split(line, "\t")
+		if len(cols) != 4 {
+			t.Fatalf("malformed line %q", line)
+		}
+		if _, err := fmt.Sscanf(cols[2], "message %d from %d", &i, &w); err != nil {
+			t.Fatalf("malformed message in %q: %v", line, err)
+		}
+		if want := fmt.Sprintf("i=%d worker=%d", i, w); cols[3] != want {
+			t.Fatalf("line %q: expected fields %q", line, want)
+		}
+	}
+}
+
+// TestMultilineConsoleAtomic logs multi-line entries from many goroutines,
+// some larger than the console buffer, and asserts each entry's lines come
+// out together, in order.
+func TestMultilineConsoleAtomic(t *testing.T) {
+	Stop()
+	t.Setenv(noColorEnv, "1")
+	out := &syncBuffer{}
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.ConsoleWriter = out
+	cfg.MultilineIndent = "| "
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	const workers, n, parts = 8, 50, 3
+	pad := strings.Repeat("x", 2000)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				msg := fmt.Sprintf("entry %d-%d", w, i)
+				for p := 0; p < parts; p++ {
+					msg += fmt.Sprintf("\npart %d of %d-%d %s", p, w, i, pad)
+				}
+				Info(msg)
+			}
+		}()
+	}
+	wg.Wait()
+	Stop()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != workers*n*(parts+1) {
+		t.Fatalf("expected %d lines, got %d", workers*n*(parts+1), len(lines))
+	}
+	for j := 0; j < len(lines); j += parts + 1 {
+		cols := strings.Split(lines[j], "\t")
+		var w, i int
+		if len(cols) != 3 {
+			t.Fatalf("line %d: expected an entry header, got %.80q", j, lines[j])
+		}
+		if _, err := fmt.Sscanf(cols[2], "entry %d-%d", &w, &i); err != nil {
+			t.Fatalf("line %d: malformed message %.80q: %v", j, cols[2], err)
+		}
+		for p := 0; p < parts; p++ {
+			want := fmt.Sprintf("| part %d of %d-%d %s", p, w, i, pad)
+			if got := lines[j+1+p]; got != want {
+				t.Fatalf("entry %d-%d: line %d interleaved: %.80q", w, i, p+1, got)
+			}
+		}
+	}
+}
+
+// TestGuardConsole interleaves application prints, each written in pieces
+// under ConsoleMutex, with log calls on the same writer and asserts every
+// line comes out whole.
+func TestGuardConsole(t *testing.T) {
+	Stop()
+	t.Setenv(noColorEnv, "1")
+	out := &syncBuffer{}
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.ConsoleWriter = out
+	cfg.GuardConsole = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	const workers, n = 4, 100
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				ConsoleMutex.Lock()
+				fmt.Fprint(out, "app ")
+				fmt.Fprintf(out, "%d-%d", w, i)
+				fmt.Fprint(out, " done\n")
+				ConsoleMutex.Unlock()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				Infof("log %d-%d", w, i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Guarded lines are flushed as they are logged, so all are present
+	// without waiting for the writer.
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2*workers*n {
+		t.Fatalf("expected %d lines, got %d", 2*workers*n, len(lines))
+	}
+	for _, line := range lines {
+		var w, i int
+		if strings.HasPrefix(line, "app ") {
+			if _, err := fmt.Sscanf(line, "app %d-%d done", &w, &i); err != nil || line != fmt.Sprintf("app %d-%d done", w, i) {
+				t.Fatalf("split application line %q", line)
+			}
+			continue
+		}
+		cols := strings.Split(line, "\t")
+		if len(cols) != 3 || cols[1] != INFO {
+			t.Fatalf("split log line %q", line)
+		}
+		if _, err := fmt.Sscanf(cols[2], "log %d-%d", &w, &i); err != nil {
+			t.Fatalf("split log line %q", line)
+		}
+	}
+}
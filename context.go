@@ -0,0 +1,51 @@
+// context.go
+//
+// # Chronos Logging - Flushing on Context Cancellation
+//
+// FlushOnContextDone ties a flush to a context, typically a request's, so
+// the entries logged while handling it are written out (including output
+// held by `Config.FlushInterval`, `Config.FlushThreshold`, or batching) as
+// soon as the context is cancelled or times out.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import "context"
+
+// FlushOnContextDone flushes the running logger once ctx is done: every
+// entry logged before then is written to its file(s), and buffered output is
+// written out. The flush covers the whole logger, not only the entries logged
+// under ctx, so entries from other goroutines are written out with them.
+//
+// Two buffers are left alone: entries held back by `Config.QuietUntilError`
+// stay held until an error occurs, and entries held by Pause stay held until
+// Resume. Writing them on cancellation would defeat both features.
+//
+// The flush runs on its own goroutine. The returned stop function cancels
+// it, reporting false if the flush has already started or no logger is
+// running.
+func FlushOnContextDone(ctx context.Context) (stop func() bool) {
+	mu.Lock()
+	l := logger.Load()
+	mu.Unlock()
+	if l == nil {
+		return func() bool { return false }
+	}
+	return context.AfterFunc(ctx, l.flushIfRunning)
+}
+
+// flushIfRunning flushes l unless it has been stopped. mu is only held to
+// read the logger, so a flush waiting on a full queue blocks neither Stop
+// nor other callers; a flush racing Stop returns once l stops (see submit).
+func (l *Logging) flushIfRunning() {
+	mu.Lock()
+	running := logger.Load() == l
+	mu.Unlock()
+	if running {
+		l.flush()
+	}
+}
@@ -0,0 +1,129 @@
+// context_test.go
+//
+// # Chronos Logging - Context Flush Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFlushOnContextDone buffers a request's entries behind a long flush
+// interval and asserts they reach disk when the request context is
+// cancelled, and that a stopped flush does nothing.
+func TestFlushOnContextDone(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.FlushInterval = time.Hour
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+	path := PathFor(time.Now())
+	lines := func() int {
+		content, _ := os.ReadFile(path)
+		return strings.Count(string(content), "\n")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	FlushOnContextDone(ctx)
+	other, cancelOther := context.WithCancel(context.Background())
+	if !FlushOnContextDone(other)() {
+		t.Error("expected stop to cancel the pending flush")
+	}
+
+	Info("request started")
+	Info("request finished")
+	cancelOther()
+	time.Sleep(100 * time.Millisecond)
+	if n := lines(); n != 0 {
+		t.Fatalf("expected output to be buffered, found %d lines on disk", n)
+	}
+
+	cancel()
+	if !waitFor(t, time.Second, func() bool { return lines() == 2 }) {
+		t.Fatalf("expected the request's entries on disk at cancellation, got %d lines", lines())
+	}
+}
+
+// TestFlushIfRunningFullQueue blocks a flush on a full queue and asserts it
+// does not hold mu while it waits.
+func TestFlushIfRunningFullQueue(t *testing.T) {
+	Stop()
+	fs := newMemFS()
+	l := newLogging(getConfig(), logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	for len(l.logChan) < cap(l.logChan) {
+		l.logChan <- Log{TimeStamp: time.Now(), Level: INFO, Message: "queued"}
+	}
+
+	flushed := make(chan struct{})
+	go func() {
+		l.flushIfRunning()
+		close(flushed)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	if !mu.TryLock() {
+		t.Fatal("expected mu to be free while the flush waits")
+	}
+	mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+	<-flushed
+	Stop()
+	<-done
+}
+
+// TestFlushOnContextDoneQuiet asserts a cancelled context leaves the entries
+// held by QuietUntilError held, and that they are written with the first
+// error as usual.
+func TestFlushOnContextDoneQuiet(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.QuietUntilError = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+	path := PathFor(time.Now())
+	read := func() string {
+		content, _ := os.ReadFile(path)
+		return string(content)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	FlushOnContextDone(ctx)
+	Info("context")
+	cancel()
+	time.Sleep(100 * time.Millisecond)
+	if content := read(); strings.Contains(content, "context") {
+		t.Fatalf("expected the quiet entry to stay held, got %q", content)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	FlushOnContextDone(ctx)
+	Error("failure")
+	cancel()
+	if !waitFor(t, time.Second, func() bool { return strings.Count(read(), "\n") == 2 }) {
+		t.Fatalf("expected the held entry and the error on disk, got %q", read())
+	}
+	if content := read(); strings.Index(content, "context") > strings.Index(content, "failure") {
+		t.Errorf("expected the held entry before the error, got %q", content)
+	}
+}
@@ -0,0 +1,104 @@
+// csv.go
+//
+// # Chronos Logging - CSV Format
+//
+// Renders entries as RFC 4180 rows when `Config.Format` is FormatCSV, for
+// loading log files into spreadsheets. Each new file starts with a header
+// row naming the columns.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"encoding/csv"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// csvFieldsColumn holds, as key=value pairs, the fields of an entry that
+// have no column of their own.
+const csvFieldsColumn = "fields"
+
+// csvColumns returns the field columns for cfg: `Config.CSVColumns` when
+// set, otherwise the keys of `Config.DefaultFields` in sorted order. Fields
+// are only known per entry, so columns cannot follow them; anything else an
+// entry carries goes to the trailing csvFieldsColumn.
+func csvColumns(cfg *Config) []string {
+	if len(cfg.CSVColumns) > 0 {
+		return append([]string(nil), cfg.CSVColumns...)
+	}
+	columns := make([]string, 0, len(cfg.DefaultFields))
+	for k := range cfg.DefaultFields {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// csvRow encodes record as a single RFC 4180 row, quoting values that hold
+// commas, quotes or line breaks, without a trailing line ending.
+func csvRow(record []string) string {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Write(record)
+	w.Flush()
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// csvHeader returns the header row written at the top of each CSV file.
+func (l *Logging) csvHeader() string {
+	record := make([]string, 0, len(l.csvColumns)+4)
+	record = append(record, "timestamp", "level", "message")
+	record = append(record, l.csvColumns...)
+	return csvRow(append(record, csvFieldsColumn))
+}
+
+// formatCSV renders an entry as a CSV row (without a trailing newline) in
+// csvHeader's column order. Field values are rendered by formatValue and
+// missing columns are left empty.
+func (l *Logging) formatCSV(log Log) string {
+	record := make([]string, 0, len(l.csvColumns)+4)
+	record = append(record,
+		l.inZone(log.TimeStamp).Format(time.RFC3339Nano),
+		log.Level,
+		log.Message,
+	)
+	var rest Fields
+	if len(log.Fields) > 0 {
+		rest = make(Fields, len(log.Fields))
+		for k, v := range log.Fields {
+			rest[k] = v
+		}
+	}
+	for _, k := range l.csvColumns {
+		v, ok := rest[k]
+		if !ok {
+			record = append(record, "")
+			continue
+		}
+		record = append(record, formatValue(v))
+		delete(rest, k)
+	}
+	record = append(record, formatFields(rest, l.config.FieldOrder))
+	return csvRow(record)
+}
+
+// writeCSVHeader writes the header row to file when it is empty, so it
+// appears once per file however often the file is reopened. Files from a
+// `Config.FS` that cannot report their size are treated as empty.
+func (l *Logging) writeCSVHeader(file io.Writer) error {
+	if s, ok := file.(interface{ Stat() (os.FileInfo, error) }); ok {
+		if info, err := s.Stat(); err == nil && info.Size() > 0 {
+			return nil
+		}
+	}
+	_, err := io.WriteString(file, l.csvHeader()+l.lineEnding())
+	return err
+}
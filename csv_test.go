@@ -0,0 +1,82 @@
+// csv_test.go
+//
+// # Chronos Logging - CSV Format Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestCSVFormat writes CSV entries across a restart and asserts the file
+// holds one header row followed by well-formed rows, with commas, quotes and
+// line breaks quoted and unlisted fields in the trailing fields column.
+func TestCSVFormat(t *testing.T) {
+	dir := t.TempDir()
+	start := func() {
+		Stop()
+		cfg := getConfig()
+		cfg.Location = dir
+		cfg.Format = FormatCSV
+		cfg.DefaultFields = Fields{"service": "api, v2"}
+		cfg.SyncForTest = true
+		if err := Init(cfg); err != nil {
+			t.Fatalf("Init failed: %v", err)
+		}
+	}
+
+	start()
+	Info("plain")
+	WithFields(Fields{"user": `say "hi"`, "id": 7}).Warn("line one\nline two, with comma")
+	path := PathFor(time.Now())
+	Stop()
+	// Reopening an existing file must not repeat the header.
+	start()
+	Error("after restart")
+	Stop()
+
+	if filepath.Ext(path) != ".csv" {
+		t.Errorf("expected a .csv file, got %s", path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("output is not well-formed CSV: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected a header and 3 rows, got %d records: %q", len(records), records)
+	}
+
+	header := []string{"timestamp", "level", "message", "service", "fields"}
+	if !reflect.DeepEqual(records[0], header) {
+		t.Errorf("expected header %q, got %q", header, records[0])
+	}
+	if _, err := time.Parse(time.RFC3339Nano, records[1][0]); err != nil {
+		t.Errorf("timestamp column: %v", err)
+	}
+	want := [][]string{
+		{INFO, "plain", "api, v2", ""},
+		{WARN, "line one\nline two, with comma", "api, v2", `id=7 user="say \"hi\""`},
+		{ERROR, "after restart", "api, v2", ""},
+	}
+	for i, w := range want {
+		if got := records[i+1][1:]; !reflect.DeepEqual(got, w) {
+			t.Errorf("row %d: expected %q, got %q", i+1, w, got)
+		}
+	}
+}
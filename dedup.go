@@ -0,0 +1,85 @@
+// dedup.go
+//
+// # Chronos Logging - Windowed Error Deduplication
+//
+// Tames error storms from retry loops. With `Config.DedupWindow`, an ERROR
+// (or more severe) entry is logged the first time its message is seen; the
+// same level and message seen again within the window is suppressed and
+// counted, even if other entries are logged in between. The first
+// occurrence after the window is logged with a "suppressed" field holding
+// that count, and opens a new window.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"sync"
+	"time"
+)
+
+// suppressedField carries the number of duplicates suppressed before an
+// entry.
+const suppressedField = "suppressed"
+
+// dedupPruneSize is the number of tracked messages above which expired
+// windows are discarded.
+const dedupPruneSize = 1024
+
+// dedupWindow is the state of one message: when its window opened and how
+// many duplicates have been suppressed since.
+type dedupWindow struct {
+	start      time.Time
+	suppressed int
+}
+
+// deduper suppresses repeated errors within a window. It is safe for
+// concurrent use.
+type deduper struct {
+	window time.Duration
+	mu     sync.Mutex
+	seen   map[string]*dedupWindow
+}
+
+// newDeduper returns a deduper for window.
+func newDeduper(window time.Duration) *deduper {
+	return &deduper{window: window, seen: map[string]*dedupWindow{}}
+}
+
+// allow reports whether log should be logged, returning it with the
+// suppressed count added when duplicates were held back. Entries below
+// ERROR always pass.
+func (d *deduper) allow(log Log) (Log, bool) {
+	if logLevels()[log.Level] < logLevels()[ERROR] {
+		return log, true
+	}
+	key := log.Level + "\x00" + log.Message
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	w := d.seen[key]
+	if w != nil && log.TimeStamp.Sub(w.start) < d.window {
+		w.suppressed++
+		return log, false
+	}
+	if w != nil && w.suppressed > 0 {
+		log = log.WithField(suppressedField, w.suppressed)
+	}
+	if w == nil && len(d.seen) >= dedupPruneSize {
+		d.prune(log.TimeStamp)
+	}
+	d.seen[key] = &dedupWindow{start: log.TimeStamp}
+	return log, true
+}
+
+// prune forgets messages whose window has closed with nothing suppressed.
+func (d *deduper) prune(now time.Time) {
+	for key, w := range d.seen {
+		if w.suppressed == 0 && now.Sub(w.start) >= d.window {
+			delete(d.seen, key)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// dedup_test.go
+//
+// # Chronos Logging - Windowed Error Deduplication Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDedupWindow repeats an error within and across the window, with other
+// entries interleaved, and asserts duplicates are suppressed and the next
+// occurrence after the window carries their count.
+func TestDedupWindow(t *testing.T) {
+	Stop()
+	c := &fakeClock{t: time.Date(2025, 5, 1, 10, 0, 0, 0, time.Local)}
+	defer useClock(c)()
+
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.SyncForTest = true
+	cfg.DedupWindow = time.Minute
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+	path := PathFor(clock())
+
+	Error("db down")
+	c.Advance(10 * time.Second)
+	for i := 0; i < 3; i++ {
+		Error("db down")
+		Info("retrying")
+		Warn("slow")
+		Warn("slow")
+	}
+	c.Advance(20 * time.Second)
+	Error("timeout")
+	c.Advance(40 * time.Second) // 70s: past the first window
+	Error("db down")
+	c.Advance(10 * time.Second)
+	Error("db down")
+	c.Advance(60 * time.Second) // 140s: past the second window
+	Error("db down")
+	c.Advance(2 * time.Minute) // nothing suppressed in the third window
+	Error("db down")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(content), "\n"), "\n") {
+		if strings.Contains(line, "\tERROR\t") {
+			got = append(got, line[strings.Index(line, "\tERROR\t")+len("\tERROR\t"):])
+		}
+	}
+	want := []string{
+		"db down",
+		"timeout",
+		"db down\tsuppressed=3",
+		"db down\tsuppressed=1",
+		"db down",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("expected errors %q, got %q", want, got)
+	}
+	if n := strings.Count(string(content), "\tINFO\tretrying"); n != 3 {
+		t.Errorf("expected interleaved entries to be logged, got %d", n)
+	}
+	if n := strings.Count(string(content), "\tWARN\tslow"); n != 6 {
+		t.Errorf("expected entries below ERROR not to be deduplicated, got %d", n)
+	}
+}
@@ -0,0 +1,162 @@
+// entry.go
+//
+// # Chronos Logging - Entry Builder
+//
+// Provides a fluent builder for structured entries:
+//
+//	chronos.Entry().Str("user", u).Int("age", 30).Msg("created")
+//
+// Typed setters collect fields in a slice and the map attached to the entry
+// is built once, when Msg or Msgf emits it. When the entry's level is
+// filtered, the builder is nil and every call on it is a no-op.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"fmt"
+	"time"
+)
+
+// fieldKind identifies which member of entryField holds the value.
+type fieldKind uint8
+
+const (
+	kindString fieldKind = iota
+	kindInt
+	kindBool
+	kindFloat
+	kindDuration
+)
+
+// entryField is a typed field held by an EntryBuilder.
+type entryField struct {
+	key  string
+	kind fieldKind
+	str  string
+	num  int64
+	flt  float64
+}
+
+// value returns the field value as stored in Log.Fields.
+func (f entryField) value() interface{} {
+	switch f.kind {
+	case kindInt:
+		return f.num
+	case kindBool:
+		return f.num != 0
+	case kindFloat:
+		return f.flt
+	case kindDuration:
+		return time.Duration(f.num)
+	default:
+		return f.str
+	}
+}
+
+// EntryBuilder accumulates fields for a single entry. Create one with Entry
+// or ErrorEntry and finish it with Msg or Msgf. A nil *EntryBuilder is valid
+// and discards everything.
+type EntryBuilder struct {
+	level  string
+	fields []entryField
+}
+
+// Entry starts an entry at INFO level. It returns nil when INFO is filtered.
+func Entry() *EntryBuilder {
+	return newEntry(INFO)
+}
+
+// ErrorEntry starts an entry at ERROR level. It returns nil when ERROR is
+// filtered.
+func ErrorEntry() *EntryBuilder {
+	return newEntry(ERROR)
+}
+
+// newEntry returns a builder for level, or nil if level is not enabled.
+func newEntry(level string) *EntryBuilder {
+	if !logger.Load().enabled(level) {
+		return nil
+	}
+	return &EntryBuilder{level: level}
+}
+
+// add appends f, returning e for chaining.
+func (e *EntryBuilder) add(f entryField) *EntryBuilder {
+	if e == nil {
+		return nil
+	}
+	e.fields = append(e.fields, f)
+	return e
+}
+
+// Str adds a string field.
+func (e *EntryBuilder) Str(key, value string) *EntryBuilder {
+	return e.add(entryField{key: key, kind: kindString, str: value})
+}
+
+// Int adds an integer field.
+func (e *EntryBuilder) Int(key string, value int) *EntryBuilder {
+	return e.add(entryField{key: key, kind: kindInt, num: int64(value)})
+}
+
+// Bool adds a boolean field.
+func (e *EntryBuilder) Bool(key string, value bool) *EntryBuilder {
+	f := entryField{key: key, kind: kindBool}
+	if value {
+		f.num = 1
+	}
+	return e.add(f)
+}
+
+// Float adds a floating point field.
+func (e *EntryBuilder) Float(key string, value float64) *EntryBuilder {
+	return e.add(entryField{key: key, kind: kindFloat, flt: value})
+}
+
+// Dur adds a duration field.
+func (e *EntryBuilder) Dur(key string, value time.Duration) *EntryBuilder {
+	return e.add(entryField{key: key, kind: kindDuration, num: int64(value)})
+}
+
+// Err adds the error's message under the key "error". A nil error adds
+// nothing.
+func (e *EntryBuilder) Err(err error) *EntryBuilder {
+	if err == nil {
+		return e
+	}
+	return e.add(entryField{key: "error", kind: kindString, str: err.Error()})
+}
+
+// Msg emits the entry with msg as its message.
+func (e *EntryBuilder) Msg(msg string) {
+	l := logger.Load()
+	if e == nil || l == nil {
+		return
+	}
+	var fields Fields
+	if len(e.fields) > 0 {
+		fields = make(Fields, len(e.fields))
+		for _, f := range e.fields {
+			fields[f.key] = f.value()
+		}
+	}
+	l.addLog(Log{
+		TimeStamp: clock(),
+		Level:     e.level,
+		Message:   msg,
+		Fields:    fields,
+	})
+}
+
+// Msgf emits the entry with a formatted message.
+func (e *EntryBuilder) Msgf(format string, args ...interface{}) {
+	if e == nil {
+		return
+	}
+	e.Msg(fmt.Sprintf(format, args...))
+}
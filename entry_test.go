@@ -0,0 +1,99 @@
+// entry_test.go
+//
+// # Chronos Logging - Entry Builder Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// logBuiltEntry runs a logger in the given format, emits one chained entry,
+// and returns the line written to the file.
+func logBuiltEntry(t *testing.T, format LogFormat) string {
+	t.Helper()
+	Stop()
+	fs := newMemFS()
+	cfg := getConfig()
+	cfg.Format = format
+	l := newLogging(cfg, logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	now := time.Now()
+	Entry().Str("user", "ada").Int("age", 30).Bool("admin", true).
+		Float("score", 1.5).Dur("took", 1500*time.Millisecond).
+		Err(errors.New("boom")).Msg("created")
+	ErrorEntry().Msgf("failed %d times", 3)
+	Stop()
+	<-done
+
+	f := fs.file(l.filePathFor(now))
+	if f == nil {
+		t.Fatal("expected log file to be written")
+	}
+	lines := strings.Split(strings.TrimSuffix(f.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", f.String())
+	}
+	if !strings.Contains(lines[1], "failed 3 times") || !strings.Contains(lines[1], ERROR) {
+		t.Errorf("unexpected ErrorEntry line %q", lines[1])
+	}
+	return lines[0]
+}
+
+// TestEntryText asserts chained fields render in the text format.
+func TestEntryText(t *testing.T) {
+	line := logBuiltEntry(t, FormatText)
+	want := "INFO\tcreated\tadmin=true age=30 error=boom score=1.5 took=1.5s user=ada"
+	if !strings.HasSuffix(line, want) {
+		t.Errorf("expected line ending %q, got %q", want, line)
+	}
+}
+
+// TestEntryJSON asserts chained fields render in the JSON format.
+func TestEntryJSON(t *testing.T) {
+	line := logBuiltEntry(t, FormatJSON)
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", line, err)
+	}
+	want := map[string]interface{}{
+		"level": INFO, "msg": "created", "user": "ada", "age": 30.0,
+		"admin": true, "score": 1.5, "took": "1.5s", "error": "boom",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("key %s: expected %v, got %v", k, v, got[k])
+		}
+	}
+	if _, err := time.Parse(time.RFC3339Nano, got["time"].(string)); err != nil {
+		t.Errorf("unexpected time %v", got["time"])
+	}
+}
+
+// TestEntryFiltered asserts a filtered builder is nil and safe to use.
+func TestEntryFiltered(t *testing.T) {
+	Stop()
+	logger.Store(newLogging(getConfig(), logLevels()[ERROR]))
+	defer Stop()
+	e := Entry()
+	if e != nil {
+		t.Fatal("expected nil builder for a filtered level")
+	}
+	e.Str("k", "v").Int("n", 1).Msg("ignored")
+}
@@ -0,0 +1,320 @@
+// fields.go
+//
+// # Chronos Logging - Structured Fields
+//
+// Defines the `Fields` type attached to log entries and how fields are
+// rendered in the text output written to the console and log files.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Fields holds structured key/value data attached to a log entry.
+type Fields map[string]interface{}
+
+// WithField returns a copy of the entry with key set to value. The original
+// entry's Fields map is never modified, so it is safe to use from middleware.
+func (l Log) WithField(key string, value interface{}) Log {
+	fields := make(Fields, len(l.Fields)+1)
+	for k, v := range l.Fields {
+		fields[k] = v
+	}
+	fields[key] = value
+	l.Fields = fields
+	return l
+}
+
+// fieldKeys returns the keys of fields in output order: keys listed in order
+// come first (in that order, when present), followed by the remaining keys
+// sorted alphabetically.
+func fieldKeys(fields Fields, order []string) []string {
+	keys := make([]string, 0, len(fields))
+	seen := make(map[string]bool, len(order))
+	for _, k := range order {
+		if _, ok := fields[k]; ok && !seen[k] {
+			keys = append(keys, k)
+			seen[k] = true
+		}
+	}
+	rest := len(keys)
+	for k := range fields {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys[rest:])
+	return keys
+}
+
+// formatFields renders fields as space-separated key=value pairs, ordered by
+// fieldKeys(), with values rendered by formatValue(). Values holding spaces,
+// '=', quotes or control characters are quoted and escaped as Go strings,
+// the way logfmt does, so every pair stays one token. An empty string is
+// returned when there are no fields.
+func formatFields(fields Fields, order []string) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	return string(appendFields(nil, fields, order))
+}
+
+// appendFields appends the pairs formatFields renders for fields to b.
+func appendFields(b []byte, fields Fields, order []string) []byte {
+	for i, k := range fieldKeys(fields, order) {
+		if i > 0 {
+			b = append(b, ' ')
+		}
+		b = append(b, k...)
+		b = append(b, '=')
+		start := len(b)
+		b = appendValue(b, fields[k])
+		if needsQuote(b[start:]) {
+			quoted := strconv.AppendQuote(nil, string(b[start:]))
+			b = append(b[:start], quoted...)
+		}
+	}
+	return b
+}
+
+// needsQuote reports whether a rendered field value must be quoted to be
+// read back as a single value.
+func needsQuote(v []byte) bool {
+	for _, c := range v {
+		if c <= ' ' || c == '=' || c == '"' || c == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// formatValue renders a field value for text output. Durations use their
+// short form (1.5s), times RFC 3339, errors their message, raw JSON as is
+// and byte slices hex; anything else is formatted with %v.
+func formatValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return string(appendValue(nil, v))
+}
+
+// appendValue appends the formatValue rendering of v to b.
+func appendValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case string:
+		return append(b, v...)
+	case int:
+		return strconv.AppendInt(b, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(b, v, 10)
+	case bool:
+		return strconv.AppendBool(b, v)
+	case time.Duration:
+		return append(b, v.String()...)
+	case time.Time:
+		return v.AppendFormat(b, time.RFC3339Nano)
+	case error:
+		return append(b, v.Error()...)
+	case json.RawMessage:
+		return append(b, v...)
+	case []byte:
+		return hex.AppendEncode(b, v)
+	default:
+		return fmt.Append(b, v)
+	}
+}
+
+// fieldsTruncatedField is the marker added by truncateFields.
+const fieldsTruncatedField = "fields_truncated"
+
+// truncateFields returns the first max fields in rendering order (see
+// fieldKeys) plus a fields_truncated marker counting the rest, so the same
+// keys are always kept. fields is returned as is when within the limit.
+func truncateFields(fields Fields, max int, order []string) Fields {
+	if len(fields) <= max {
+		return fields
+	}
+	keys := fieldKeys(fields, order)
+	kept := make(Fields, max+1)
+	for _, k := range keys[:max] {
+		kept[k] = fields[k]
+	}
+	kept[fieldsTruncatedField] = len(keys) - max
+	return kept
+}
+
+// withDefaults returns fields merged over the logger's default fields.
+// Fields set on the entry win on key collisions; the caller's map is not
+// modified.
+func (l *Logging) withDefaults(fields Fields) Fields {
+	l.defaultsMu.RLock()
+	defer l.defaultsMu.RUnlock()
+	if len(l.defaults) == 0 {
+		return fields
+	}
+	merged := make(Fields, len(l.defaults)+len(fields))
+	for k, v := range l.defaults {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return merged
+}
+
+// AddDefaultField adds a field to every subsequent entry of the running
+// logger, alongside `Config.DefaultFields`. It replaces any default with the
+// same key.
+func AddDefaultField(key string, value interface{}) {
+	mu.Lock()
+	l := logger.Load()
+	mu.Unlock()
+	if l == nil {
+		return
+	}
+	l.defaultsMu.Lock()
+	defer l.defaultsMu.Unlock()
+	if l.defaults == nil {
+		l.defaults = Fields{}
+	}
+	l.defaults[key] = value
+}
+
+// Lazy is a field value computed only when the entry is emitted, i.e. after
+// it has passed level filtering and middleware. Use it for values that are
+// expensive to build:
+//
+//	chronos.WithFields(chronos.Fields{"state": chronos.Lazy(dumpState)}).Debug("tick")
+//
+// The function is called once per entry, on the goroutine that logged it,
+// and its result replaces the Lazy before the entry reaches the console,
+// files, or sinks.
+type Lazy func() interface{}
+
+// resolveFields returns fields with every Lazy value evaluated. The map is
+// copied only when it holds a Lazy, so callers' maps are never modified.
+func resolveFields(fields Fields) Fields {
+	var resolved Fields
+	for k, v := range fields {
+		lazy, ok := v.(Lazy)
+		if !ok {
+			continue
+		}
+		if resolved == nil {
+			resolved = make(Fields, len(fields))
+			for k, v := range fields {
+				resolved[k] = v
+			}
+		}
+		resolved[k] = lazy()
+	}
+	if resolved == nil {
+		return fields
+	}
+	return resolved
+}
+
+// FieldLogger logs entries carrying a fixed set of fields. Create one with
+// WithFields. A FieldLogger is never modified after creation, so it can be
+// shared between goroutines and extended with its WithFields method.
+type FieldLogger struct {
+	fields Fields
+}
+
+// WithFields returns a FieldLogger whose entries carry a copy of fields.
+func WithFields(fields Fields) *FieldLogger {
+	copied := make(Fields, len(fields))
+	for k, v := range fields {
+		copied[k] = v
+	}
+	return &FieldLogger{fields: copied}
+}
+
+// WithFields returns a new FieldLogger carrying f's fields and fields, with
+// fields taking precedence for keys in both. f is left unchanged.
+func (f *FieldLogger) WithFields(fields Fields) *FieldLogger {
+	merged := make(Fields, len(f.fields)+len(fields))
+	for k, v := range f.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &FieldLogger{fields: merged}
+}
+
+// log sends an entry at level with the logger's fields.
+func (f *FieldLogger) log(level, msg string) {
+	logger.Load().addLog(Log{
+		TimeStamp: clock(),
+		Level:     level,
+		Message:   msg,
+		Fields:    f.fields,
+	})
+}
+
+// Debug logs a message at DEBUG level with the logger's fields.
+func (f *FieldLogger) Debug(msg string) { f.log(DEBUG, msg) }
+
+// Info logs a message at INFO level with the logger's fields.
+func (f *FieldLogger) Info(msg string) { f.log(INFO, msg) }
+
+// Warn logs a message at WARN level with the logger's fields.
+func (f *FieldLogger) Warn(msg string) { f.log(WARN, msg) }
+
+// Error logs a message at ERROR level with the logger's fields.
+func (f *FieldLogger) Error(msg string) { f.log(ERROR, msg) }
+
+// Fatal logs a message at FATAL level with the logger's fields.
+func (f *FieldLogger) Fatal(msg string) { f.log(FATAL, msg) }
+
+// Debugf logs a formatted message at DEBUG level with the logger's fields.
+func (f *FieldLogger) Debugf(format string, args ...interface{}) {
+	if !logger.Load().enabled(DEBUG) {
+		return
+	}
+	f.log(DEBUG, fmt.Sprintf(format, args...))
+}
+
+// Infof logs a formatted message at INFO level with the logger's fields.
+func (f *FieldLogger) Infof(format string, args ...interface{}) {
+	if !logger.Load().enabled(INFO) {
+		return
+	}
+	f.log(INFO, fmt.Sprintf(format, args...))
+}
+
+// Warnf logs a formatted message at WARN level with the logger's fields.
+func (f *FieldLogger) Warnf(format string, args ...interface{}) {
+	if !logger.Load().enabled(WARN) {
+		return
+	}
+	f.log(WARN, fmt.Sprintf(format, args...))
+}
+
+// Errorf logs a formatted message at ERROR level with the logger's fields.
+func (f *FieldLogger) Errorf(format string, args ...interface{}) {
+	if !logger.Load().enabled(ERROR) {
+		return
+	}
+	f.log(ERROR, fmt.Sprintf(format, args...))
+}
+
+// Fatalf logs a formatted message at FATAL level with the logger's fields.
+func (f *FieldLogger) Fatalf(format string, args ...interface{}) {
+	if !logger.Load().enabled(FATAL) {
+		return
+	}
+	f.log(FATAL, fmt.Sprintf(format, args...))
+}
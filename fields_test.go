@@ -0,0 +1,229 @@
+// fields_test.go
+//
+// # Chronos Logging - Structured Field Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFieldOrder asserts configured keys are rendered first, in order, with
+// the rest sorted, regardless of map iteration order.
+func TestFieldOrder(t *testing.T) {
+	cfg := getConfig()
+	cfg.FieldOrder = []string{"request_id", "user"}
+	l := newLogging(cfg, logLevels()[INFO])
+
+	log := Log{
+		TimeStamp: time.Now(),
+		Level:     INFO,
+		Message:   "ordered",
+		Fields:    Fields{"zeta": 1, "user": "bob", "alpha": 2, "request_id": "r1"},
+	}
+	for i := 0; i < 20; i++ {
+		line := l.formatLine(log)
+		want := "\trequest_id=r1 user=bob alpha=2 zeta=1"
+		if !strings.HasSuffix(line, want) {
+			t.Fatalf("expected line to end with %q, got %q", want, line)
+		}
+	}
+}
+
+// TestFieldOrderMissingKeys ensures keys named in FieldOrder but absent from
+// the entry are skipped.
+func TestFieldOrderMissingKeys(t *testing.T) {
+	got := formatFields(Fields{"b": 1, "a": 2}, []string{"request_id", "b"})
+	if got != "b=1 a=2" {
+		t.Errorf("unexpected field rendering: %q", got)
+	}
+}
+
+// TestFormatValue renders one field of each specially handled type.
+func TestFormatValue(t *testing.T) {
+	at := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	fields := Fields{
+		"bytes":   []byte{0xde, 0xad, 0xbe, 0xef},
+		"elapsed": 1500 * time.Millisecond,
+		"err":     errors.New("connection refused"),
+		"when":    at,
+	}
+	got := formatFields(fields, nil)
+	want := `bytes=deadbeef elapsed=1.5s err="connection refused" when=2025-03-01T10:30:00Z`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// TestFieldQuoting asserts values that would not read back as one token are
+// quoted and escaped, and plain values are left bare.
+func TestFieldQuoting(t *testing.T) {
+	fields := Fields{
+		"a": "plain",
+		"b": "two words",
+		"c": "k=v",
+		"d": "tab\there",
+		"e": "line\nbreak",
+		"f": `say "hi"`,
+		"g": "",
+	}
+	got := formatFields(fields, nil)
+	want := `a=plain b="two words" c="k=v" d="tab\there" e="line\nbreak" f="say \"hi\"" g=`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// TestLazyField asserts a Lazy value is not evaluated for a filtered entry
+// and is evaluated once, with its result written, for an emitted entry.
+func TestLazyField(t *testing.T) {
+	Stop()
+	fs := newMemFS()
+	l := newLogging(getConfig(), logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	calls := 0
+	lazy := Lazy(func() interface{} {
+		calls++
+		return "expensive"
+	})
+	now := time.Now()
+	WithFields(Fields{"state": lazy}).Debug("filtered")
+	if calls != 0 {
+		t.Fatalf("expected lazy field to be skipped for a filtered entry, got %d calls", calls)
+	}
+	WithFields(Fields{"state": lazy}).Info("emitted")
+	Stop()
+	<-done
+
+	if calls != 1 {
+		t.Errorf("expected lazy field to be evaluated once, got %d calls", calls)
+	}
+	if got := fs.file(l.filePathFor(now)).String(); !strings.Contains(got, "emitted\tstate=expensive") {
+		t.Errorf("expected resolved field in file, got %q", got)
+	}
+}
+
+// TestDefaultFields asserts default fields appear on entries without fields
+// and are overridden by fields set on the entry.
+func TestDefaultFields(t *testing.T) {
+	Stop()
+	fs := newMemFS()
+	cfg := getConfig()
+	cfg.DefaultFields = Fields{"env": "prod"}
+	l := newLogging(cfg, logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	now := time.Now()
+	Info("plain")
+	WithFields(Fields{"env": "staging"}).Info("override")
+	AddDefaultField("version", "1.2")
+	Info("added")
+	Stop()
+	<-done
+
+	lines := strings.Split(strings.TrimSuffix(fs.file(l.filePathFor(now)).String(), "\n"), "\n")
+	want := []string{"plain\tenv=prod", "override\tenv=staging", "added\tenv=prod version=1.2"}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got %q", len(want), lines)
+	}
+	for i, w := range want {
+		if !strings.HasSuffix(lines[i], w) {
+			t.Errorf("line %d: expected suffix %q, got %q", i, w, lines[i])
+		}
+	}
+	if len(cfg.DefaultFields) != 1 {
+		t.Errorf("expected config defaults to be left untouched, got %v", cfg.DefaultFields)
+	}
+}
+
+// TestMaxFields logs 200 fields with MaxFields=50 and asserts exactly the
+// first 50 sorted keys are written, plus a marker counting the other 150.
+func TestMaxFields(t *testing.T) {
+	Stop()
+	fs := newMemFS()
+	cfg := getConfig()
+	cfg.MaxFields = 50
+	l := newLogging(cfg, logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	fields := Fields{}
+	for i := 0; i < 200; i++ {
+		fields[fmt.Sprintf("k%03d", i)] = i
+	}
+	now := time.Now()
+	WithFields(fields).Info("huge")
+	Stop()
+	<-done
+
+	line := strings.TrimSuffix(fs.file(l.filePathFor(now)).String(), "\n")
+	pairs := strings.Fields(line[strings.LastIndex(line, "\t")+1:])
+	if len(pairs) != 51 {
+		t.Fatalf("expected 50 fields plus the marker, got %d", len(pairs))
+	}
+	want := map[string]bool{"fields_truncated=150": true}
+	for i := 0; i < 50; i++ {
+		want[fmt.Sprintf("k%03d=%d", i, i)] = true
+	}
+	for _, pair := range pairs {
+		if !want[pair] {
+			t.Errorf("unexpected field %q", pair)
+		}
+	}
+}
+
+// TestFieldLoggerCompose chains WithFields and asserts the child carries
+// both sets with its own keys winning, and that parent and child share no
+// map.
+func TestFieldLoggerCompose(t *testing.T) {
+	base := WithFields(Fields{"service": "api", "env": "prod"})
+	child := base.WithFields(Fields{"env": "staging", "request_id": "r1"})
+	grandchild := child.WithFields(Fields{"user": "bob"})
+
+	want := Fields{"service": "api", "env": "staging", "request_id": "r1"}
+	if !reflect.DeepEqual(child.fields, want) {
+		t.Errorf("expected %v, got %v", want, child.fields)
+	}
+	if len(grandchild.fields) != 4 || grandchild.fields["env"] != "staging" {
+		t.Errorf("unexpected grandchild fields %v", grandchild.fields)
+	}
+	if !reflect.DeepEqual(base.fields, Fields{"service": "api", "env": "prod"}) {
+		t.Errorf("parent was modified: %v", base.fields)
+	}
+
+	child.fields["mutated"] = true
+	if _, ok := base.fields["mutated"]; ok {
+		t.Error("parent and child share a fields map")
+	}
+	if _, ok := grandchild.fields["mutated"]; ok {
+		t.Error("child and grandchild share a fields map")
+	}
+}
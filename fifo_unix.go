@@ -0,0 +1,53 @@
+// fifo_unix.go
+//
+// # Chronos Logging - Named Pipes (Unix)
+//
+// Opens named pipes for writing without blocking, so a missing or slow
+// reader cannot stall the writer goroutine.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+
+//go:build !windows
+
+package chronos
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"time"
+)
+
+// fifoWriteTimeout bounds how long a write waits for a full pipe to drain
+// before the entry is dropped.
+const fifoWriteTimeout = 100 * time.Millisecond
+
+// isFIFO reports whether path exists and is a named pipe.
+func isFIFO(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.Mode()&os.ModeNamedPipe != 0
+}
+
+// openFIFO opens the named pipe at path for writing. It fails immediately
+// (ENXIO) rather than blocking when no reader has the pipe open.
+func openFIFO(path string) (io.WriteCloser, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &fifoFile{File: f}, nil
+}
+
+// fifoFile is a named pipe whose writes give up after fifoWriteTimeout.
+type fifoFile struct {
+	*os.File
+}
+
+func (f *fifoFile) Write(p []byte) (int, error) {
+	f.SetWriteDeadline(time.Now().Add(fifoWriteTimeout))
+	return f.File.Write(p)
+}
@@ -0,0 +1,109 @@
+// fifo_unix_test.go
+//
+// # Chronos Logging - Named Pipe Tests (Unix)
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+
+//go:build !windows
+
+package chronos
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestFIFO writes to a named pipe at Location: entries logged before a reader
+// connects are dropped without hanging, and later entries reach the reader.
+func TestFIFO(t *testing.T) {
+	Stop()
+	tempDir, err := os.MkdirTemp("", "fifo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	fifo := filepath.Join(tempDir, "pipe")
+	if err := syscall.Mkfifo(fifo, 0600); err != nil {
+		t.Skipf("mkfifo not available: %v", err)
+	}
+
+	var errs []error
+	var errsMu sync.Mutex
+	cfg := getConfig()
+	cfg.Location = fifo
+	cfg.ErrorHandler = func(err error) {
+		errsMu.Lock()
+		errs = append(errs, err)
+		errsMu.Unlock()
+	}
+	l := newLogging(cfg, logLevels()[INFO])
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+	defer func() {
+		Stop()
+		<-done
+	}()
+
+	Info("nobody listening")
+	Info("still nobody")
+	l.flush()
+	errsMu.Lock()
+	if len(errs) != 1 {
+		t.Errorf("expected one open error without a reader, got %v", errs)
+	}
+	errsMu.Unlock()
+
+	r, err := os.OpenFile(fifo, os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var received strings.Builder
+	var receivedMu sync.Mutex
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := r.Read(buf)
+			receivedMu.Lock()
+			received.Write(buf[:n])
+			receivedMu.Unlock()
+			if err != nil && n == 0 {
+				// No writer connected yet, or it went away; keep polling.
+				time.Sleep(5 * time.Millisecond)
+				if errors.Is(err, os.ErrClosed) {
+					return
+				}
+			}
+		}
+	}()
+
+	Info("hello pipe")
+	Warn("second entry")
+	ok := waitFor(t, 2*time.Second, func() bool {
+		receivedMu.Lock()
+		defer receivedMu.Unlock()
+		return strings.Contains(received.String(), "second entry")
+	})
+	receivedMu.Lock()
+	defer receivedMu.Unlock()
+	if !ok || !strings.Contains(received.String(), "INFO\thello pipe") {
+		t.Fatalf("expected entries from the pipe, got %q", received.String())
+	}
+	if strings.Contains(received.String(), "nobody") {
+		t.Errorf("expected entries without a reader to be dropped, got %q", received.String())
+	}
+}
@@ -0,0 +1,30 @@
+// fifo_windows.go
+//
+// # Chronos Logging - Named Pipes (Windows)
+//
+// Unix named pipes are not available on Windows.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+
+//go:build windows
+
+package chronos
+
+import (
+	"errors"
+	"io"
+)
+
+// isFIFO always reports false on Windows.
+func isFIFO(path string) bool {
+	return false
+}
+
+// openFIFO is not supported on Windows.
+func openFIFO(path string) (io.WriteCloser, error) {
+	return nil, errors.New("named pipes are not supported on windows")
+}
@@ -0,0 +1,55 @@
+// file.go
+//
+// # Chronos Logging - Caller-Owned Files
+//
+// NewWithFile points the logger at a file the application has already
+// opened, for apps that manage their own handles (special flags, inherited
+// descriptors). Rotation, directories, and retention are skipped, and the
+// file is left open on Stop because the caller owns it.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// NewWithFile starts the package-level logger writing formatted lines to f,
+// stopping any logger already running. level is the minimum severity
+// logged, e.g. int(LevelInfo). Entries go to the console as well, as with
+// Init. Stop stops the logger but does not close f.
+func NewWithFile(f *os.File, level int) {
+	l := newLogging(&Config{FilePeriod: LogPeriodHour}, levelRank(level))
+	l.file = f
+	mu.Lock()
+	defer mu.Unlock()
+	if prev := logger.Load(); prev != nil {
+		prev.stop()
+	}
+	logger.Store(l)
+	go l.start()
+}
+
+// writeFile writes line to the caller's file. Entries carrying done are
+// synced to disk before writeFile returns.
+func (l *Logging) writeFile(log Log, line string) error {
+	if _, err := io.WriteString(l.file, line); err != nil {
+		err = fmt.Errorf("could not write to log file %s: %w", l.file.Name(), err)
+		l.reportError(err)
+		return err
+	}
+	if log.done != nil {
+		if err := l.file.Sync(); err != nil {
+			err = fmt.Errorf("could not sync log file %s: %w", l.file.Name(), err)
+			l.reportError(err)
+			return err
+		}
+	}
+	return nil
+}
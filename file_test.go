@@ -0,0 +1,64 @@
+// file_test.go
+//
+// # Chronos Logging - Caller-Owned File Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestNewWithFile passes a temp file and asserts the previous logger is
+// stopped, entries at or above the level are written to the file, and Stop
+// leaves it open for the caller.
+func TestNewWithFile(t *testing.T) {
+	Stop()
+	f, err := os.CreateTemp("", "withfile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	prev := logger.Load()
+	NewWithFile(f, int(LevelInfo))
+	if l := logger.Load(); l == nil || l == prev || l.file != f {
+		t.Fatal("expected NewWithFile to install the logger")
+	}
+	if !prev.stopped.Load() {
+		t.Error("expected NewWithFile to stop the previous logger")
+	}
+	Info("hello")
+	Debug("hidden")
+	if err := ErrorSync("synced"); err != nil {
+		t.Fatalf("ErrorSync failed: %v", err)
+	}
+	Stop()
+
+	if _, err := f.WriteString("owned by caller\n"); err != nil {
+		t.Fatalf("expected the file to stay open after Stop: %v", err)
+	}
+	content, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", lines)
+	}
+	if !strings.HasSuffix(lines[0], "\tINFO\thello") || !strings.HasSuffix(lines[1], "\tERROR\tsynced") || lines[2] != "owned by caller" {
+		t.Errorf("unexpected file contents %q", lines)
+	}
+}
@@ -0,0 +1,84 @@
+// filter.go
+//
+// # Chronos Logging - Field Filters
+//
+// Keeps or drops entries by their structured fields, e.g. logging only the
+// entries for one tenant during a targeted debugging session. Filters are
+// set with `Config.FieldFilters` and checked once the entry's fields,
+// including caller details and default fields, are attached.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FilterOp is the comparison a FieldFilter makes.
+type FilterOp string
+
+// Supported filter operations.
+//
+// - FilterEquals    => the field is present and its value equals Value
+// - FilterNotEquals => the field is missing or its value differs from Value
+// - FilterExists    => the field is present, whatever its value
+// - FilterMissing   => the field is not present
+const (
+	FilterEquals    FilterOp = "eq"
+	FilterNotEquals FilterOp = "ne"
+	FilterExists    FilterOp = "exists"
+	FilterMissing   FilterOp = "missing"
+)
+
+// FieldFilter is a predicate on one field of an entry. Values are compared
+// with their fmt.Sprint form, so {Key: "status", Op: FilterEquals, Value:
+// "500"} matches the int 500. Lazy values are compared unevaluated.
+type FieldFilter struct {
+	Key   string   `json:"key"`
+	Op    FilterOp `json:"op"`
+	Value string   `json:"value,omitempty"`
+}
+
+// matches reports whether fields satisfy f.
+func (f FieldFilter) matches(fields Fields) bool {
+	v, ok := fields[f.Key]
+	switch f.Op {
+	case FilterEquals:
+		return ok && fmt.Sprint(v) == f.Value
+	case FilterNotEquals:
+		return !ok || fmt.Sprint(v) != f.Value
+	case FilterExists:
+		return ok
+	case FilterMissing:
+		return !ok
+	}
+	return false
+}
+
+// validate checks that f names a key and a supported operation.
+func (f FieldFilter) validate() error {
+	if f.Key == "" {
+		return errors.New("field filter has no key")
+	}
+	switch f.Op {
+	case FilterEquals, FilterNotEquals, FilterExists, FilterMissing:
+		return nil
+	}
+	return fmt.Errorf("invalid field filter operation for %s: %s", f.Key, f.Op)
+}
+
+// keep reports whether an entry with fields passes every
+// `Config.FieldFilters` entry.
+func (l *Logging) keep(fields Fields) bool {
+	for _, f := range l.config.FieldFilters {
+		if !f.matches(fields) {
+			return false
+		}
+	}
+	return true
+}
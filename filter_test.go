@@ -0,0 +1,62 @@
+// filter_test.go
+//
+// # Chronos Logging - Field Filter Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import "testing"
+
+// TestFieldFilters keeps only tenant=acme entries and asserts the others are
+// dropped, whether the field is missing or holds another value.
+func TestFieldFilters(t *testing.T) {
+	Stop()
+	sink := &memSink{}
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.SyncForTest = true
+	cfg.CustomSinks = []Sink{sink}
+	cfg.FieldFilters = []FieldFilter{{Key: "tenant", Op: FilterEquals, Value: "acme"}}
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	Info("no tenant")
+	WithFields(Fields{"tenant": "globex"}).Info("other tenant")
+	WithFields(Fields{"tenant": "acme", "n": 1}).Info("kept")
+	WithFields(Fields{"tenant": "acme"}).Error("also kept")
+	Stop()
+
+	if len(sink.logs) != 2 || sink.logs[0].Message != "kept" || sink.logs[1].Message != "also kept" {
+		t.Fatalf("expected only the acme entries, got %+v", sink.logs)
+	}
+
+	fields := Fields{"status": 500}
+	cases := []struct {
+		filter FieldFilter
+		want   bool
+	}{
+		{FieldFilter{Key: "status", Op: FilterEquals, Value: "500"}, true},
+		{FieldFilter{Key: "status", Op: FilterNotEquals, Value: "500"}, false},
+		{FieldFilter{Key: "user", Op: FilterNotEquals, Value: "bob"}, true},
+		{FieldFilter{Key: "status", Op: FilterExists}, true},
+		{FieldFilter{Key: "status", Op: FilterMissing}, false},
+		{FieldFilter{Key: "user", Op: FilterMissing}, true},
+	}
+	for _, c := range cases {
+		if got := c.filter.matches(fields); got != c.want {
+			t.Errorf("%+v: expected %v, got %v", c.filter, c.want, got)
+		}
+	}
+
+	for _, bad := range []FieldFilter{{Op: FilterExists}, {Key: "k", Op: "like"}} {
+		cfg := getConfig()
+		cfg.FieldFilters = []FieldFilter{bad}
+		if _, err := resolveConfig(cfg); err == nil {
+			t.Errorf("expected an error for %+v", bad)
+		}
+	}
+}
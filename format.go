@@ -0,0 +1,174 @@
+// format.go
+//
+// # Chronos Logging - Line Formatting
+//
+// Renders log entries into the text lines written to log files and, with
+// color applied, to the console.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogFormat selects how entries are encoded in log files.
+type LogFormat string
+
+// Supported file formats.
+//
+// - FormatText   => tab-separated text lines (default)
+// - FormatBinary => compact length-prefixed records, see binary.go
+// - FormatJSON   => one JSON object per line, see json.go
+// - FormatCSV    => RFC 4180 rows under a header row, see csv.go
+const (
+	FormatText   LogFormat = "text"
+	FormatBinary LogFormat = "binary"
+	FormatJSON   LogFormat = "json"
+	FormatCSV    LogFormat = "csv"
+)
+
+// LineEnding selects the terminator written after each line in text and
+// JSON log files.
+type LineEnding string
+
+// Supported line endings.
+//
+// - LineEndingLF   => "\n" (default)
+// - LineEndingCRLF => "\r\n", for Windows tools that expect it
+const (
+	LineEndingLF   LineEnding = "lf"
+	LineEndingCRLF LineEnding = "crlf"
+)
+
+// Time-of-day layouts used in text lines. The precise layout is used when the
+// write time is rendered, so queue latency below a second is visible.
+const (
+	timeLayout    = "15:04:05"
+	preciseLayout = "15:04:05.000000"
+)
+
+// defaultFieldSeparator separates the columns of a text line unless
+// `Config.FieldSeparator` overrides it.
+const defaultFieldSeparator = "\t"
+
+// separator returns the column separator for text lines.
+func (l *Logging) separator() string {
+	if l.config.FieldSeparator == "" {
+		return defaultFieldSeparator
+	}
+	return l.config.FieldSeparator
+}
+
+// lineEnding returns the terminator for lines written to log files. The
+// console always uses "\n".
+func (l *Logging) lineEnding() string {
+	if l.config.LineEnding == LineEndingCRLF {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// inZone returns t in `Config.Timezone`, or unchanged when none is set.
+// Rendered times and rotation boundaries both use it.
+func (l *Logging) inZone(t time.Time) time.Time {
+	if l.loc == nil {
+		return t
+	}
+	return t.In(l.loc)
+}
+
+// formatFile renders an entry as the line written to log files and the tee
+// (without a trailing newline), in JSON with FormatJSON, as a CSV row with
+// FormatCSV and as text otherwise. Binary records are encoded separately by
+// the writer.
+func (l *Logging) formatFile(log Log) string {
+	switch l.config.Format {
+	case FormatJSON:
+		return l.formatJSON(log)
+	case FormatCSV:
+		return l.formatCSV(log)
+	}
+	return l.formatLine(log)
+}
+
+// fileLine returns the formatFile line for log with its line ending.
+func (l *Logging) fileLine(log Log) string {
+	b := getBuffer()
+	defer putBuffer(b)
+	switch l.config.Format {
+	case FormatJSON:
+		*b = append(*b, l.formatJSON(log)...)
+	case FormatCSV:
+		*b = append(*b, l.formatCSV(log)...)
+	default:
+		*b = l.appendLine(*b, log)
+	}
+	*b = append(*b, l.lineEnding()...)
+	return string(*b)
+}
+
+// newlineEscaper writes line breaks as escape sequences, see
+// `Config.EscapeNewlines`.
+var newlineEscaper = strings.NewReplacer("\n", `\n`, "\r", `\r`)
+
+// message prepares msg for a text line: line breaks are escaped with
+// `Config.EscapeNewlines`, or else continuation lines are prefixed with
+// `Config.MultilineIndent`.
+func (l *Logging) message(msg string) string {
+	if !strings.ContainsAny(msg, "\r\n") {
+		return msg
+	}
+	if l.config.EscapeNewlines {
+		return newlineEscaper.Replace(msg)
+	}
+	if l.config.MultilineIndent != "" {
+		return strings.ReplaceAll(msg, "\n", "\n"+l.config.MultilineIndent)
+	}
+	return msg
+}
+
+// formatLine renders an entry as a line of columns separated by
+// `Config.FieldSeparator` (a tab by default), without a trailing newline:
+// time, level, message, and, when present, the fields ordered according to
+// `Config.FieldOrder`. With `Config.IncludeWriteTime`, lines produced by the
+// writer carry the call time and write time as two precise columns. With
+// `Config.IncludeSeverityNumber`, the level's numeric value follows it as
+// its own column.
+func (l *Logging) formatLine(log Log) string {
+	b := getBuffer()
+	defer putBuffer(b)
+	*b = l.appendLine(*b, log)
+	return string(*b)
+}
+
+// appendLine appends the line formatLine renders for log to b.
+func (l *Logging) appendLine(b []byte, log Log) []byte {
+	sep := l.separator()
+	if l.config.IncludeWriteTime && !log.WriteTime.IsZero() {
+		b = l.inZone(log.TimeStamp).AppendFormat(b, preciseLayout)
+		b = append(b, sep...)
+		b = l.inZone(log.WriteTime).AppendFormat(b, preciseLayout)
+	} else {
+		b = l.inZone(log.TimeStamp).AppendFormat(b, timeLayout)
+	}
+	b = append(b, sep...)
+	b = append(b, log.Level...)
+	b = append(b, sep...)
+	if l.config.IncludeSeverityNumber {
+		b = strconv.AppendInt(b, int64(levelSeverity(logLevels()[log.Level])), 10)
+		b = append(b, sep...)
+	}
+	b = append(b, l.message(log.Message)...)
+	if len(log.Fields) > 0 {
+		b = append(b, sep...)
+		b = appendFields(b, log.Fields, l.config.FieldOrder)
+	}
+	return b
+}
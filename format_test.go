@@ -0,0 +1,141 @@
+// format_test.go
+//
+// # Chronos Logging - Line Formatting Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestFieldSeparator asserts a custom separator sits between the time, level,
+// and message columns in both file and console output, and that lines still
+// parse back.
+func TestFieldSeparator(t *testing.T) {
+	cfg := getConfig()
+	cfg.FieldSeparator = "|"
+	l := newLogging(cfg, logLevels()[INFO])
+
+	ts := time.Date(2025, 3, 1, 10, 4, 5, 0, time.Local)
+	log := Log{TimeStamp: ts, Level: INFO, Message: "ready", Fields: Fields{"port": 80}}
+	line := l.formatLine(log)
+	if line != "10:04:05|INFO|ready|port=80" {
+		t.Errorf("unexpected file line %q", line)
+	}
+	if out := l.formatConsole(log); !strings.Contains(out, "10:04:05|INFO|ready") {
+		t.Errorf("unexpected console line %q", out)
+	}
+
+	parsed, err := l.parseLine(line, ts)
+	if err != nil {
+		t.Fatalf("could not parse %q: %v", line, err)
+	}
+	if !parsed.TimeStamp.Equal(ts) || parsed.Level != INFO || parsed.Message != "ready" {
+		t.Errorf("unexpected parsed entry %+v", parsed)
+	}
+}
+
+// TestInitRejectsMultilineSeparator ensures a separator containing a line
+// break is rejected.
+func TestInitRejectsMultilineSeparator(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.FieldSeparator = "\n"
+	if err := Init(cfg); err == nil {
+		Stop()
+		t.Fatal("expected error for separator with a line break")
+	}
+}
+
+// TestLineEndingCRLF asserts file lines end in CRLF when configured, that
+// the console keeps LF, and that Tail still reads the file back.
+func TestLineEndingCRLF(t *testing.T) {
+	Stop()
+	tempDir, err := os.MkdirTemp("", "crlf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.LineEnding = LineEndingCRLF
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+	console := &syncBuffer{}
+	logger.Load().console = newConsoleWriter(console, false)
+	path := logger.Load().filePathFor(time.Now())
+
+	Info("first")
+	Warn("second")
+	logger.Load().flush()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "\tfirst\r\n") || !strings.HasSuffix(lines[1], "\tsecond\r\n") {
+		t.Errorf("expected CRLF terminators, got %q", content)
+	}
+	if strings.Contains(console.String(), "\r") {
+		t.Errorf("expected LF on the console, got %q", console.String())
+	}
+	logs, err := Tail(2)
+	if err != nil || len(logs) != 2 || logs[1].Message != "second" {
+		t.Errorf("unexpected Tail result %v, %v", logs, err)
+	}
+}
+
+// TestMultilineIndent logs a three-line message and asserts the continuation
+// lines carry the indent in the file, and that EscapeNewlines takes
+// precedence over the indent.
+func TestMultilineIndent(t *testing.T) {
+	Stop()
+	tempDir, err := os.MkdirTemp("", "multiline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.MultilineIndent = "  | "
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	Info("table:\nname  size\nfoo   42")
+	content, err := os.ReadFile(PathFor(clock()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", lines)
+	}
+	if !strings.HasSuffix(lines[0], "\tINFO\ttable:") {
+		t.Errorf("unexpected first line %q", lines[0])
+	}
+	if lines[1] != "  | name  size" || lines[2] != "  | foo   42" {
+		t.Errorf("expected indented continuation lines, got %q", lines[1:])
+	}
+
+	cfg.EscapeNewlines = true
+	line := logger.Load().formatLine(Log{Level: INFO, Message: "a\nb\r\nc"})
+	if !strings.HasSuffix(line, `a\nb\r\nc`) {
+		t.Errorf("expected escaped newlines, got %q", line)
+	}
+}
@@ -0,0 +1,54 @@
+// goroutine.go
+//
+// # Chronos Logging - Goroutine IDs
+//
+// Tags entries with the ID of the goroutine that logged them when
+// `Config.IncludeGoroutineID` is set. Go does not expose the ID, so it is
+// read from the header of the goroutine's stack trace.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// goroutineField is the field key holding the goroutine ID.
+const goroutineField = "goroutine"
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// "goroutine N [running]:" line that starts its stack trace, or 0 if the
+// line cannot be parsed.
+func goroutineID() int64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// withGoroutineID returns fields with the calling goroutine's ID added,
+// unless the entry already sets the field. fields is not modified.
+func withGoroutineID(fields Fields) Fields {
+	if _, ok := fields[goroutineField]; ok {
+		return fields
+	}
+	tagged := make(Fields, len(fields)+1)
+	for k, v := range fields {
+		tagged[k] = v
+	}
+	tagged[goroutineField] = goroutineID()
+	return tagged
+}
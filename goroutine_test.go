@@ -0,0 +1,71 @@
+// goroutine_test.go
+//
+// # Chronos Logging - Goroutine ID Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// TestIncludeGoroutineID logs from several goroutines and asserts each entry
+// carries the ID of the goroutine that logged it, distinct per goroutine.
+func TestIncludeGoroutineID(t *testing.T) {
+	Stop()
+	sink := &memSink{}
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.SyncForTest = true
+	cfg.IncludeGoroutineID = true
+	cfg.CustomSinks = []Sink{sink}
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	const n = 8
+	want := make(map[string]int64, n)
+	var wantMu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			msg := fmt.Sprintf("from %d", i)
+			wantMu.Lock()
+			want[msg] = goroutineID()
+			wantMu.Unlock()
+			Info(msg)
+			WithFields(Fields{"n": i}).Info(msg)
+		}(i)
+	}
+	wg.Wait()
+	Stop()
+
+	if len(sink.logs) != 2*n {
+		t.Fatalf("expected %d entries, got %d", 2*n, len(sink.logs))
+	}
+	seen := map[int64]string{}
+	for _, log := range sink.logs {
+		id, ok := log.Fields[goroutineField].(int64)
+		if !ok || id <= 0 {
+			t.Fatalf("entry %q has no goroutine ID: %v", log.Message, log.Fields)
+		}
+		if id != want[log.Message] {
+			t.Errorf("entry %q: expected goroutine %d, got %d", log.Message, want[log.Message], id)
+		}
+		if other, dup := seen[id]; dup && other != log.Message {
+			t.Errorf("goroutine %d reported for both %q and %q", id, other, log.Message)
+		}
+		seen[id] = log.Message
+	}
+	if len(seen) != n {
+		t.Errorf("expected %d distinct goroutine IDs, got %d", n, len(seen))
+	}
+}
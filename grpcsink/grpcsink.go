@@ -0,0 +1,532 @@
+// grpcsink.go
+//
+// # Chronos Logging - gRPC Streaming Sink
+//
+// Streams entries to a central collector over a gRPC bidirectional stream
+// (see proto/collector.proto). The sink is a `chronos.RemoteSink` kept in
+// its own module, so applications that do not stream over gRPC do not pull
+// in its dependencies:
+//
+//	sink, err := grpcsink.New(grpcsink.Config{Target: "collector:4317"})
+//	if err != nil {
+//		return err
+//	}
+//	cfg.RemoteSinks = []chronos.RemoteSink{sink}
+//
+// The logger closes the sink once it stops and has handed it every entry.
+//
+// Send only queues the entry; batches are sent from the sink's own
+// goroutine. When the queue is full Send fails, so the logger retries the
+// entry according to `Config.RemoteBuffer` while file logging carries on
+// and acts as the durable fallback. Failed batches reconnect with
+// exponential backoff and are retried up to MaxRetries times.
+//
+// The messages are small enough that they are encoded by hand with
+// protowire rather than generated code.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package grpcsink
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/markoxley/chronos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Config configures streaming of entries to a gRPC collector.
+type Config struct {
+	// Target is the collector address, e.g. "collector:4317".
+	Target string `json:"target"`
+
+	// TLS enables transport security with the given configuration. When nil
+	// the connection is unencrypted.
+	TLS *tls.Config `json:"-"`
+
+	// BatchSize is the maximum number of entries sent per message.
+	// Defaults to 100.
+	BatchSize int `json:"batch_size"`
+
+	// FlushInterval is the longest an entry waits before a partial batch is
+	// sent. Defaults to one second.
+	FlushInterval time.Duration `json:"flush_interval"`
+
+	// QueueSize bounds the entries held for the sink, including those waiting
+	// to be retried. Defaults to 10000.
+	QueueSize int `json:"queue_size"`
+
+	// MaxRetries is how often a failed batch is resent before it is dropped.
+	// Defaults to 3; a negative value disables retries.
+	MaxRetries int `json:"max_retries"`
+
+	// Backoff is the delay before the first retry, doubled for each further
+	// attempt up to 10 seconds. Defaults to 500ms.
+	Backoff time.Duration `json:"backoff"`
+
+	// Timeout, when positive, bounds each batch send. A send still running
+	// after the timeout is cancelled and counts as failed.
+	Timeout time.Duration `json:"timeout"`
+
+	// OnError receives connection and send errors. When nil they are
+	// printed to stderr.
+	OnError func(error) `json:"-"`
+}
+
+const (
+	streamMethod   = "/chronos.v1.LogCollector/Stream"
+	maxBackoff     = 10 * time.Second
+	closeTimeout   = 5 * time.Second
+	defaultBatch   = 100
+	defaultQueue   = 10000
+	defaultPeriod  = time.Second
+	defaultRetries = 3
+	defaultBackoff = 500 * time.Millisecond
+)
+
+var streamDesc = grpc.StreamDesc{
+	StreamName:    "Stream",
+	ServerStreams: true,
+	ClientStreams: true,
+}
+
+var (
+	// ErrQueueFull is returned by Send when the sink's queue is full, so the
+	// logger retries the entry later.
+	ErrQueueFull = errors.New("gRPC sink queue is full")
+	// ErrClosed is returned by Send after Close.
+	ErrClosed = errors.New("gRPC sink is closed")
+)
+
+// Sink batches entries and streams them to the collector.
+type Sink struct {
+	cfg     Config
+	conn    *grpc.ClientConn
+	entries chan chronos.Log
+	done    chan struct{}
+	dropped atomic.Uint64
+
+	// closeMu guards closed against Sends racing Close.
+	closeMu sync.RWMutex
+	closed  bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Owned by run().
+	stream       grpc.ClientStream
+	streamCancel context.CancelFunc
+	drained      chan struct{}
+	failing      bool
+}
+
+// New creates the client connection and starts the sending goroutine. The
+// connection itself is established lazily.
+func New(cfg Config) (*Sink, error) {
+	if cfg.Target == "" {
+		return nil, errors.New("grpcsink: Target is required")
+	}
+	if cfg.BatchSize < 0 || cfg.QueueSize < 0 || cfg.FlushInterval < 0 || cfg.Backoff < 0 || cfg.Timeout < 0 {
+		return nil, errors.New("grpcsink: BatchSize, QueueSize, FlushInterval, Backoff and Timeout must not be negative")
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = defaultBatch
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = defaultPeriod
+	}
+	if cfg.QueueSize == 0 {
+		cfg.QueueSize = defaultQueue
+	}
+	switch {
+	case cfg.MaxRetries == 0:
+		cfg.MaxRetries = defaultRetries
+	case cfg.MaxRetries < 0:
+		cfg.MaxRetries = 0
+	}
+	if cfg.Backoff == 0 {
+		cfg.Backoff = defaultBackoff
+	}
+
+	creds := insecure.NewCredentials()
+	if cfg.TLS != nil {
+		creds = credentials.NewTLS(cfg.TLS)
+	}
+	conn, err := grpc.NewClient(cfg.Target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("could not create gRPC client for %s: %w", cfg.Target, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Sink{
+		cfg:     cfg,
+		conn:    conn,
+		entries: make(chan chronos.Log, cfg.QueueSize),
+		done:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	go s.run()
+	return s, nil
+}
+
+// Send queues log for the next batch without blocking. It returns
+// ErrQueueFull when the queue is full and ErrClosed after Close.
+func (s *Sink) Send(_ context.Context, log chronos.Log) error {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+	if s.closed {
+		return ErrClosed
+	}
+	select {
+	case s.entries <- log:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Dropped returns the number of entries dropped after their batch ran out
+// of retries, or because the retry queue was full.
+func (s *Sink) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close sends the queued entries, giving the collector a bounded amount of
+// time to receive them, and releases the connection. The logger calls it
+// when it stops; calling it again does nothing.
+func (s *Sink) Close() error {
+	s.closeMu.Lock()
+	if s.closed {
+		s.closeMu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.entries)
+	s.closeMu.Unlock()
+
+	select {
+	case <-s.done:
+	case <-time.After(closeTimeout):
+		s.cancel()
+		<-s.done
+	}
+	s.cancel()
+	return s.conn.Close()
+}
+
+// run batches queued entries and sends them, reconnecting with backoff.
+func (s *Sink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]chronos.Log, 0, s.cfg.BatchSize)
+	backoff := s.cfg.Backoff
+	attempts := 0
+	var retry <-chan time.Time
+
+	for {
+		select {
+		case log, ok := <-s.entries:
+			if !ok {
+				if len(batch) > 0 {
+					s.deliver(batch)
+				}
+				s.closeStream()
+				return
+			}
+			if len(batch) >= s.cfg.QueueSize {
+				batch = batch[1:]
+				s.dropped.Add(1)
+			}
+			batch = append(batch, log)
+			if retry != nil || len(batch) < s.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if retry != nil || len(batch) == 0 {
+				continue
+			}
+		case <-retry:
+			retry = nil
+		case <-s.ctx.Done():
+			return
+		}
+
+		for len(batch) > 0 {
+			n := min(len(batch), s.cfg.BatchSize)
+			if err := s.deliver(batch[:n]); err != nil {
+				attempts++
+				if attempts <= s.cfg.MaxRetries {
+					retry = time.After(backoff)
+					backoff = min(backoff*2, maxBackoff)
+					break
+				}
+				s.dropped.Add(uint64(n))
+				s.report(fmt.Errorf("gRPC sink %s dropped %d entries after %d retries: %w", s.cfg.Target, n, s.cfg.MaxRetries, err))
+			}
+			batch = batch[n:]
+			backoff = s.cfg.Backoff
+			attempts = 0
+		}
+		if len(batch) == 0 {
+			batch = make([]chronos.Log, 0, s.cfg.BatchSize)
+		}
+	}
+}
+
+// deliver sends one batch, opening the stream first if necessary. On failure
+// the stream is discarded so the next attempt reconnects.
+func (s *Sink) deliver(batch []chronos.Log) error {
+	if s.stream == nil {
+		ctx, cancel := context.WithCancel(s.ctx)
+		stream, err := s.conn.NewStream(ctx, &streamDesc, streamMethod, grpc.ForceCodec(codec{}))
+		if err != nil {
+			cancel()
+			s.fail(err)
+			return err
+		}
+		s.stream = stream
+		s.streamCancel = cancel
+		s.drained = make(chan struct{})
+		go func(stream grpc.ClientStream, drained chan struct{}) {
+			defer close(drained)
+			for stream.RecvMsg(&ackMessage{}) == nil {
+			}
+		}(stream, s.drained)
+	}
+
+	if err := s.sendBatch(batch); err != nil {
+		s.streamCancel()
+		s.stream = nil
+		s.fail(err)
+		return err
+	}
+	s.failing = false
+	return nil
+}
+
+// sendBatch writes one batch to the stream. With a timeout configured, a
+// send that does not complete in time cancels the stream and fails.
+func (s *Sink) sendBatch(entries []chronos.Log) error {
+	if s.cfg.Timeout <= 0 {
+		return s.stream.SendMsg(&batchMessage{entries: entries})
+	}
+	result := make(chan error, 1)
+	go func(stream grpc.ClientStream) {
+		result <- stream.SendMsg(&batchMessage{entries: entries})
+	}(s.stream)
+	timer := time.NewTimer(s.cfg.Timeout)
+	defer timer.Stop()
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		s.streamCancel()
+		<-result
+		return fmt.Errorf("send timed out after %v: %w", s.cfg.Timeout, context.DeadlineExceeded)
+	}
+}
+
+// closeStream half-closes the stream and waits for the collector to finish
+// reading it.
+func (s *Sink) closeStream() {
+	if s.stream == nil {
+		return
+	}
+	s.stream.CloseSend()
+	select {
+	case <-s.drained:
+	case <-s.ctx.Done():
+	}
+	s.streamCancel()
+	s.stream = nil
+}
+
+// fail reports the first error of a run of failures.
+func (s *Sink) fail(err error) {
+	if s.failing {
+		return
+	}
+	s.failing = true
+	s.report(fmt.Errorf("gRPC sink %s: %w", s.cfg.Target, err))
+}
+
+// report passes err to `Config.OnError`, or prints it to stderr.
+func (s *Sink) report(err error) {
+	if s.cfg.OnError != nil {
+		s.cfg.OnError(err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+}
+
+// batchMessage is the Batch message from proto/collector.proto.
+type batchMessage struct {
+	entries []chronos.Log
+}
+
+// ackMessage is the Ack message from proto/collector.proto.
+type ackMessage struct {
+	received uint64
+}
+
+// codec encodes the collector messages using the protobuf wire format.
+type codec struct{}
+
+func (codec) Name() string { return "proto" }
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	switch m := v.(type) {
+	case *batchMessage:
+		var b []byte
+		for _, log := range m.entries {
+			b = protowire.AppendTag(b, 1, protowire.BytesType)
+			b = protowire.AppendBytes(b, marshalEntry(log))
+		}
+		return b, nil
+	case *ackMessage:
+		var b []byte
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		return protowire.AppendVarint(b, m.received), nil
+	}
+	return nil, fmt.Errorf("grpcsink codec: cannot marshal %T", v)
+}
+
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	switch m := v.(type) {
+	case *batchMessage:
+		return consumeFields(data, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+			if num != 1 || typ != protowire.BytesType {
+				return protowire.ConsumeFieldValue(num, typ, b), nil
+			}
+			raw, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return n, nil
+			}
+			log, err := unmarshalEntry(raw)
+			if err != nil {
+				return 0, err
+			}
+			m.entries = append(m.entries, log)
+			return n, nil
+		})
+	case *ackMessage:
+		return consumeFields(data, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+			if num != 1 || typ != protowire.VarintType {
+				return protowire.ConsumeFieldValue(num, typ, b), nil
+			}
+			v, n := protowire.ConsumeVarint(b)
+			m.received = v
+			return n, nil
+		})
+	}
+	return fmt.Errorf("grpcsink codec: cannot unmarshal %T", v)
+}
+
+// marshalEntry encodes an Entry message. Field values are rendered as strings.
+func marshalEntry(log chronos.Log) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(log.TimeStamp.UnixNano()))
+	b = protowire.AppendTag(b, 2, protowire.BytesType)
+	b = protowire.AppendString(b, log.Level)
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendString(b, log.Message)
+	for k, v := range log.Fields {
+		var kv []byte
+		kv = protowire.AppendTag(kv, 1, protowire.BytesType)
+		kv = protowire.AppendString(kv, k)
+		kv = protowire.AppendTag(kv, 2, protowire.BytesType)
+		kv = protowire.AppendString(kv, fmt.Sprint(v))
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendBytes(b, kv)
+	}
+	return b
+}
+
+// unmarshalEntry decodes an Entry message.
+func unmarshalEntry(data []byte) (chronos.Log, error) {
+	var log chronos.Log
+	err := consumeFields(data, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch {
+		case num == 1 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			log.TimeStamp = time.Unix(0, int64(v))
+			return n, nil
+		case num == 2 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			log.Level = v
+			return n, nil
+		case num == 3 && typ == protowire.BytesType:
+			v, n := protowire.ConsumeString(b)
+			log.Message = v
+			return n, nil
+		case num == 4 && typ == protowire.BytesType:
+			raw, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return n, nil
+			}
+			var key, value string
+			err := consumeFields(raw, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+				if typ != protowire.BytesType || (num != 1 && num != 2) {
+					return protowire.ConsumeFieldValue(num, typ, b), nil
+				}
+				v, n := protowire.ConsumeString(b)
+				if num == 1 {
+					key = v
+				} else {
+					value = v
+				}
+				return n, nil
+			})
+			if err != nil {
+				return 0, err
+			}
+			if log.Fields == nil {
+				log.Fields = chronos.Fields{}
+			}
+			log.Fields[key] = value
+			return n, nil
+		}
+		return protowire.ConsumeFieldValue(num, typ, b), nil
+	})
+	return log, err
+}
+
+// consumeFields walks the fields of a protobuf message, calling fn with the
+// bytes following each tag. fn returns the number of bytes it consumed, or a
+// negative protowire error code.
+func consumeFields(data []byte, fn func(protowire.Number, protowire.Type, []byte) (int, error)) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		m, err := fn(num, typ, data)
+		if err != nil {
+			return err
+		}
+		if m < 0 {
+			return protowire.ParseError(m)
+		}
+		data = data[m:]
+	}
+	return nil
+}
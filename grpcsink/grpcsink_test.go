@@ -0,0 +1,183 @@
+// grpcsink_test.go
+//
+// # Chronos Logging - gRPC Sink Tests
+//
+// Runs an in-process collector implementing proto/collector.proto and
+// asserts streamed entries arrive intact.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package grpcsink
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/markoxley/chronos"
+	"google.golang.org/grpc"
+)
+
+// testCollector records every entry streamed to it.
+type testCollector struct {
+	mu      sync.Mutex
+	entries []chronos.Log
+}
+
+func (c *testCollector) received() []chronos.Log {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]chronos.Log(nil), c.entries...)
+}
+
+// serve starts a gRPC server for the collector on lis.
+func (c *testCollector) serve(lis net.Listener) *grpc.Server {
+	srv := grpc.NewServer(grpc.ForceServerCodec(codec{}))
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "chronos.v1.LogCollector",
+		HandlerType: (*interface{})(nil),
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "Stream",
+			ServerStreams: true,
+			ClientStreams: true,
+			Handler: func(_ interface{}, stream grpc.ServerStream) error {
+				for {
+					var b batchMessage
+					if err := stream.RecvMsg(&b); err != nil {
+						return nil
+					}
+					c.mu.Lock()
+					c.entries = append(c.entries, b.entries...)
+					c.mu.Unlock()
+					if err := stream.SendMsg(&ackMessage{received: uint64(len(b.entries))}); err != nil {
+						return err
+					}
+				}
+			},
+		}},
+	}, struct{}{})
+	go srv.Serve(lis)
+	return srv
+}
+
+// startLogger starts a logger streaming to target through a Sink and
+// returns a function that stops the logger and waits for it to close the
+// sink.
+func startLogger(t *testing.T, target string) func() {
+	t.Helper()
+	sink, err := New(Config{Target: target, FlushInterval: 20 * time.Millisecond, OnError: func(error) {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &chronos.Config{
+		AppName:    "test",
+		Location:   t.TempDir(),
+		FilePeriod: chronos.LogPeriodHour,
+		Level:      chronos.DEBUG,
+	}
+	cfg.RemoteSinks = []chronos.RemoteSink{sink}
+	if err := chronos.Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	return func() {
+		chronos.Stop()
+		<-sink.done
+	}
+}
+
+// TestSinkStreamsEntries asserts entries are received by the collector with
+// their levels, messages, and fields.
+func TestSinkStreamsEntries(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collector := &testCollector{}
+	srv := collector.serve(lis)
+	defer srv.Stop()
+
+	stop := startLogger(t, lis.Addr().String())
+	chronos.Info("service started")
+	chronos.WithFields(chronos.Fields{"free": 5}).Warn("disk low")
+	chronos.Error("request failed")
+	stop()
+
+	got := collector.received()
+	if len(got) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(got))
+	}
+	want := []struct{ level, msg string }{{chronos.INFO, "service started"}, {chronos.WARN, "disk low"}, {chronos.ERROR, "request failed"}}
+	for i, w := range want {
+		if got[i].Level != w.level || got[i].Message != w.msg {
+			t.Errorf("entry %d: expected %s %q, got %s %q", i, w.level, w.msg, got[i].Level, got[i].Message)
+		}
+	}
+	if got[1].Fields["free"] != "5" {
+		t.Errorf("expected field free=5, got %v", got[1].Fields)
+	}
+	if time.Since(got[0].TimeStamp) > time.Minute {
+		t.Errorf("unexpected timestamp %v", got[0].TimeStamp)
+	}
+}
+
+// TestSinkReconnects logs while the collector is down and asserts the
+// entries are delivered once it comes up.
+func TestSinkReconnects(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+
+	stop := startLogger(t, addr)
+	defer stop()
+	chronos.Info("while down")
+	time.Sleep(150 * time.Millisecond)
+
+	lis, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("could not rebind %s: %v", addr, err)
+	}
+	collector := &testCollector{}
+	srv := collector.serve(lis)
+	defer srv.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(collector.received()) < 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	got := collector.received()
+	if len(got) != 1 {
+		t.Fatalf("expected entry after reconnect, got %d", len(got))
+	}
+	if got[0].Message != "while down" {
+		t.Errorf("unexpected entry %+v", got[0])
+	}
+}
+
+// TestSendQueueFull asserts Send reports a full queue so the logger retries
+// the entry, and fails after Close.
+func TestSendQueueFull(t *testing.T) {
+	sink, err := New(Config{Target: "127.0.0.1:1", QueueSize: 1, FlushInterval: time.Hour, OnError: func(error) {}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Fill the channel; run() may already hold one entry in its batch.
+	var full bool
+	for i := 0; i < 3 && !full; i++ {
+		full = sink.Send(context.Background(), chronos.Log{Level: chronos.INFO}) == ErrQueueFull
+	}
+	if !full {
+		t.Error("expected ErrQueueFull once the queue is full")
+	}
+	sink.Close()
+	if err := sink.Send(context.Background(), chronos.Log{Level: chronos.INFO}); err != ErrClosed {
+		t.Errorf("expected ErrClosed after Close, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+// heartbeat.go
+//
+// # Chronos Logging - Heartbeat
+//
+// With `Config.Heartbeat`, a background goroutine started by Init logs a
+// "heartbeat" entry at `Config.HeartbeatLevel` (DEBUG by default) on every
+// tick until the logger stops. A quiet service still shows it is alive and
+// that logging works, and the current file keeps being written to. Like any
+// entry, heartbeats below the logger's level are filtered out.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import "time"
+
+// heartbeatMessage is the message of each heartbeat entry.
+const heartbeatMessage = "heartbeat"
+
+// heartbeat logs a heartbeat entry every interval until l stops. A tick
+// that races Stop is dropped rather than logged while l shuts down.
+func (l *Logging) heartbeat(every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			select {
+			case <-l.quit:
+				return
+			default:
+			}
+			if l.stopped.Load() {
+				return
+			}
+			l.addLog(Log{
+				TimeStamp: clock(),
+				Level:     l.config.HeartbeatLevel,
+				Message:   heartbeatMessage,
+			})
+		case <-l.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// heartbeat_test.go
+//
+// # Chronos Logging - Heartbeat Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHeartbeat asserts heartbeat entries are written on a short interval
+// and that none follow Stop, not even into the pre-Init buffer.
+func TestHeartbeat(t *testing.T) {
+	Stop()
+	fs := newMemFS()
+	cfg := getConfig()
+	cfg.Location = filepath.Join(t.TempDir(), "memory")
+	cfg.FS = fs
+	cfg.SyncForTest = true
+	cfg.Heartbeat = 5 * time.Millisecond
+	cfg.HeartbeatLevel = "info"
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	path := PathFor(time.Now())
+
+	beats := func() int {
+		f := fs.file(path)
+		if f == nil {
+			return 0
+		}
+		return strings.Count(f.String(), "\tINFO\theartbeat\n")
+	}
+	if !waitFor(t, time.Second, func() bool { return beats() >= 1 }) {
+		Stop()
+		t.Fatal("expected at least one heartbeat entry")
+	}
+	Stop()
+	stopped := beats()
+	time.Sleep(30 * time.Millisecond)
+	if n := beats(); n != stopped {
+		t.Errorf("expected no heartbeats after Stop, got %d more", n-stopped)
+	}
+	for _, log := range takePreInit() {
+		if log.Message == heartbeatMessage {
+			t.Error("expected no heartbeat to be kept for the next Init")
+		}
+	}
+}
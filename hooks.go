@@ -0,0 +1,102 @@
+// hooks.go
+//
+// # Chronos Logging - Asynchronous Handler Dispatch
+//
+// By default the handler registered with SetHandler runs inline on the
+// logging goroutine, so a slow handler (a webhook, a metrics push) slows
+// every log call. With `Config.HookWorkers` set, entries are instead passed
+// to a pool of that many workers through a bounded queue. When the queue is
+// full the handler call is dropped and counted (see HookDropped); logging
+// itself never waits for the handler. Stop waits for the workers to finish
+// the queued calls, and entries emitted after that are not handed to the
+// handler.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// hookQueueSize is the capacity of the queue feeding the hook workers.
+const hookQueueSize = 1024
+
+// hookPool runs the external handler on a fixed set of worker goroutines.
+// closeMu guards closing queue against concurrent dispatches.
+type hookPool struct {
+	queue   chan Log
+	closeMu sync.RWMutex
+	closed  bool
+	wg      sync.WaitGroup
+	dropped atomic.Uint64
+}
+
+// newHookPool starts workers goroutines that run the external handler until
+// the pool is stopped.
+func newHookPool(workers int) *hookPool {
+	p := &hookPool{queue: make(chan Log, hookQueueSize)}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.run()
+	}
+	return p
+}
+
+// run is the worker loop. It returns once the queue is closed and empty.
+func (p *hookPool) run() {
+	defer p.wg.Done()
+	for log := range p.queue {
+		callHandler(log)
+	}
+}
+
+// dispatch queues log for the workers, dropping it if the queue is full.
+// Entries dispatched after stop are ignored.
+func (p *hookPool) dispatch(log Log) {
+	p.closeMu.RLock()
+	defer p.closeMu.RUnlock()
+	if p.closed {
+		return
+	}
+	select {
+	case p.queue <- log:
+	default:
+		p.dropped.Add(1)
+	}
+}
+
+// stop closes the queue and waits for the workers to finish the entries in
+// it.
+func (p *hookPool) stop() {
+	p.closeMu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.closeMu.Unlock()
+	p.wg.Wait()
+}
+
+// callHandler passes the entry to the handler registered with SetHandler.
+func callHandler(log Log) {
+	if handler := externalHandler; handler != nil {
+		handler(log.TimeStamp, log.Level, log.Message)
+	}
+}
+
+// HookDropped returns how many handler calls the running logger has dropped
+// because the hook worker queue was full (see `Config.HookWorkers`).
+func HookDropped() uint64 {
+	mu.Lock()
+	defer mu.Unlock()
+	l := logger.Load()
+	if l == nil || l.hooks == nil {
+		return 0
+	}
+	return l.hooks.dropped.Load()
+}
@@ -0,0 +1,102 @@
+// hooks_test.go
+//
+// # Chronos Logging - Asynchronous Handler Dispatch Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestHookWorkersSlowHook blocks the handler and asserts file writes are not
+// held up, that overflowing handler calls are dropped and counted, and that
+// every call is either handled or counted as dropped.
+func TestHookWorkersSlowHook(t *testing.T) {
+	Stop()
+	gate := make(chan struct{})
+	var handled atomic.Uint64
+	SetHandler(func(time.Time, string, string) {
+		<-gate
+		handled.Add(1)
+	})
+	defer SetHandler(nil)
+
+	fs := newMemFS()
+	cfg := getConfig()
+	cfg.HookWorkers = 2
+	l := newLogging(cfg, logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	const n = 3000
+	now := time.Now()
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		Infof("entry %d", i)
+	}
+	l.flush()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("file writes were delayed by the slow hook: %v", elapsed)
+	}
+	if lines := strings.Count(fs.file(l.filePathFor(now)).String(), "\n"); lines != n {
+		t.Errorf("expected %d lines while the hook is blocked, got %d", n, lines)
+	}
+	dropped := HookDropped()
+	if dropped == 0 {
+		t.Error("expected slow-hook drops to be counted")
+	}
+
+	close(gate)
+	Stop()
+	<-done
+	if got := handled.Load() + dropped; got != n {
+		t.Errorf("expected handled+dropped = %d, got %d+%d", n, handled.Load(), dropped)
+	}
+}
+
+// TestHookWorkersStop asserts Stop waits for queued handler calls and that
+// entries emitted afterwards never reach the handler.
+func TestHookWorkersStop(t *testing.T) {
+	Stop()
+	var handled atomic.Uint64
+	SetHandler(func(time.Time, string, string) {
+		time.Sleep(time.Millisecond)
+		handled.Add(1)
+	})
+	defer SetHandler(nil)
+
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.SyncForTest = true
+	cfg.HookWorkers = 1
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	l := logger.Load()
+	const n = 20
+	for i := 0; i < n; i++ {
+		Infof("entry %d", i)
+	}
+	Stop()
+	if got := handled.Load(); got != n {
+		t.Errorf("expected Stop to wait for %d handler calls, got %d", n, got)
+	}
+	l.emit(Log{TimeStamp: time.Now(), Level: INFO, Message: "late"})
+	time.Sleep(10 * time.Millisecond)
+	if got := handled.Load(); got != n {
+		t.Errorf("expected no handler call after Stop, got %d", got-n)
+	}
+}
@@ -0,0 +1,74 @@
+// http.go
+//
+// # Chronos Logging - HTTP Middleware
+//
+// Provides `HTTPMiddleware`, which logs one structured entry per request
+// with its method, path, status, duration, and response size.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"net/http"
+	"time"
+)
+
+// statusRecorder wraps a ResponseWriter to capture the status code and the
+// number of body bytes written.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += n
+	return n, err
+}
+
+// Unwrap exposes the underlying ResponseWriter to http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// HTTPMiddleware logs each request handled by next once it completes. The
+// entry carries the fields method, path, status, duration, and bytes, and is
+// logged at INFO, or at ERROR when the status is 5xx.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, req)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		entry := WithFields(Fields{
+			"method":   req.Method,
+			"path":     req.URL.Path,
+			"status":   rec.status,
+			"duration": time.Since(start),
+			"bytes":    rec.bytes,
+		})
+		if rec.status >= http.StatusInternalServerError {
+			entry.Error("http request")
+			return
+		}
+		entry.Info("http request")
+	})
+}
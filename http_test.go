@@ -0,0 +1,83 @@
+// http_test.go
+//
+// # Chronos Logging - HTTP Middleware Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestHTTPMiddleware asserts a 200 logs at INFO with the request fields and a
+// 500 logs at ERROR.
+func TestHTTPMiddleware(t *testing.T) {
+	Stop()
+	resetMiddleware()
+	defer resetMiddleware()
+
+	var captured []Log
+	var capturedMu sync.Mutex
+	Use(func(next HandlerFunc) HandlerFunc {
+		return func(log Log) {
+			capturedMu.Lock()
+			captured = append(captured, log)
+			capturedMu.Unlock()
+			next(log)
+		}
+	})
+
+	l := newLogging(getConfig(), logLevels()[INFO])
+	l.opener = newMemFS().open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+	defer func() {
+		Stop()
+		<-done
+	}()
+
+	handler := HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			http.Error(w, "broken", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/fail", nil))
+
+	capturedMu.Lock()
+	defer capturedMu.Unlock()
+	if len(captured) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(captured))
+	}
+
+	ok := captured[0]
+	if ok.Level != INFO {
+		t.Errorf("expected INFO for 200, got %s", ok.Level)
+	}
+	if ok.Fields["method"] != http.MethodGet || ok.Fields["path"] != "/ok" ||
+		ok.Fields["status"] != http.StatusOK || ok.Fields["bytes"] != 5 {
+		t.Errorf("unexpected fields %v", ok.Fields)
+	}
+	if _, isDuration := ok.Fields["duration"].(time.Duration); !isDuration {
+		t.Errorf("expected duration field, got %v", ok.Fields["duration"])
+	}
+
+	fail := captured[1]
+	if fail.Level != ERROR || fail.Fields["status"] != http.StatusInternalServerError {
+		t.Errorf("expected ERROR with status 500, got %s %v", fail.Level, fail.Fields)
+	}
+}
@@ -0,0 +1,74 @@
+// inline.go
+//
+// # Chronos Logging - Deterministic Test Mode
+//
+// With `Config.SyncForTest`, no writer goroutine is started and the queue is
+// bypassed entirely: each entry is written to its file(s) by the goroutine
+// that logged it, before the logging call returns. Tests can then read the
+// output straight away, with no sleeps and no waiting on Stop. Unlike
+// ErrorSync, nothing is synced to disk; the point is ordering, not
+// durability.
+//
+// Batching, buffered writes, idle handle closing, and reopening on SIGUSR1
+// all rely on the writer goroutine and have no effect in this mode.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import "errors"
+
+// submit hands log to the writer: straight to inline() with
+// `Config.SyncForTest`, otherwise through the queue. Once l has stopped,
+// log.done receives errStopped instead.
+func (l *Logging) submit(log Log) {
+	if l.config.SyncForTest {
+		l.inline(log)
+		return
+	}
+	l.sendMu.RLock()
+	defer l.sendMu.RUnlock()
+	if l.stopped.Load() {
+		if log.done != nil {
+			log.done <- errStopped
+		}
+		return
+	}
+	l.logChan <- log
+}
+
+// inline processes log on the calling goroutine, as start() would have, and
+// sends the result to log.done when set.
+func (l *Logging) inline(log Log) {
+	l.inlineMu.Lock()
+	if l.stopped.Load() {
+		l.inlineMu.Unlock()
+		if log.done != nil {
+			log.done <- errStopped
+		}
+		return
+	}
+	err := l.handle(log)
+	l.drained()
+	l.inlineMu.Unlock()
+	if log.done != nil {
+		log.done <- err
+	}
+}
+
+// stopInline closes the outputs and files left open by inline writes.
+func (l *Logging) stopInline() {
+	l.inlineMu.Lock()
+	defer l.inlineMu.Unlock()
+	l.stopped.Store(true)
+	l.closeOutputs()
+	l.files.Close()
+	l.compressing.Wait()
+}
+
+// errStopped is returned to callers waiting on an entry submitted after the
+// logger stopped.
+var errStopped = errors.New("logger stopped")
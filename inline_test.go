@@ -0,0 +1,60 @@
+// inline_test.go
+//
+// # Chronos Logging - Deterministic Test Mode Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSyncForTest asserts each entry is in the file as soon as the logging
+// call returns, with buffering configured and without flushing or stopping.
+func TestSyncForTest(t *testing.T) {
+	Stop()
+	tempDir, err := os.MkdirTemp("", "inline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.SyncForTest = true
+	cfg.FlushInterval = time.Hour
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+	path := PathFor(clock())
+
+	Info("first")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected the file to exist immediately: %v", err)
+	}
+	if !strings.Contains(string(data), "first") {
+		t.Fatalf("expected first entry in file, got %q", data)
+	}
+
+	Warnf("second %d", 2)
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "WARN") || !strings.Contains(lines[1], "second 2") {
+		t.Errorf("expected second entry on its own line, got %q", lines)
+	}
+	if err := SelfTest(); err != nil {
+		t.Errorf("SelfTest failed: %v", err)
+	}
+}
@@ -0,0 +1,262 @@
+// json.go
+//
+// # Chronos Logging - JSON Format
+//
+// Renders entries as line-delimited JSON objects when `Config.Format` is
+// FormatJSON. Each object carries the time, level, and message followed by
+// the entry's fields as top-level keys; a field named like a standard key is
+// written with a "fields." prefix so it cannot shadow it. ReadJSONLog reads
+// such output back.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// jsonKeyNames are the canonical JSON keys `Config.JSONKeys` can rename.
+var jsonKeyNames = map[string]bool{
+	"time":      true,
+	"level":     true,
+	"msg":       true,
+	callerField: true,
+}
+
+// jsonFieldPrefix is prepended to the key of a field that would collide
+// with a standard key (see jsonStandardKey).
+const jsonFieldPrefix = "fields."
+
+// jsonKey returns the key written for the canonical JSON key name, as
+// renamed by `Config.JSONKeys`.
+func (l *Logging) jsonKey(name string) string {
+	return jsonKeyIn(l.config.JSONKeys, name)
+}
+
+// jsonKeyIn returns the key for the canonical JSON key name under the
+// renaming keys.
+func jsonKeyIn(keys map[string]string, name string) string {
+	if key, ok := keys[name]; ok {
+		return key
+	}
+	return name
+}
+
+// jsonStandardKey reports whether key is one of the keys a JSON object
+// carries besides the fields under the renaming keys: time, level and msg,
+// the optional write_time, severity and hash, and a renamed caller key.
+func jsonStandardKey(keys map[string]string, key string) bool {
+	switch key {
+	case jsonKeyIn(keys, "time"), jsonKeyIn(keys, "level"), jsonKeyIn(keys, "msg"),
+		"write_time", "severity", "hash":
+		return true
+	}
+	caller := jsonKeyIn(keys, callerField)
+	return caller != callerField && key == caller
+}
+
+// formatJSON renders an entry as a single-line JSON object (without a
+// trailing newline). Fields follow `Config.FieldOrder`, then sorted order.
+// With `Config.IncludeSeverityNumber` the level's numeric value is added as
+// "severity". The time, level, msg and caller keys can be renamed with
+// `Config.JSONKeys`, and `Config.Preset` may rename the levels. Fields named
+// like a standard key get jsonFieldPrefix.
+func (l *Logging) formatJSON(log Log) string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	writeJSONPair(&sb, l.jsonKey("time"), l.inZone(log.TimeStamp).Format(time.RFC3339Nano))
+	if l.config.IncludeWriteTime && !log.WriteTime.IsZero() {
+		sb.WriteByte(',')
+		writeJSONPair(&sb, "write_time", l.inZone(log.WriteTime).Format(time.RFC3339Nano))
+	}
+	sb.WriteByte(',')
+	writeJSONPair(&sb, l.jsonKey("level"), l.jsonLevel(log.Level))
+	if l.config.IncludeSeverityNumber {
+		sb.WriteByte(',')
+		writeJSONPair(&sb, "severity", levelSeverity(logLevels()[log.Level]))
+	}
+	sb.WriteByte(',')
+	writeJSONPair(&sb, l.jsonKey("msg"), log.Message)
+	for _, k := range fieldKeys(log.Fields, l.config.FieldOrder) {
+		key := k
+		switch {
+		case k == callerField:
+			key = l.jsonKey(callerField)
+		case jsonStandardKey(l.config.JSONKeys, k):
+			key = jsonFieldPrefix + k
+		}
+		sb.WriteByte(',')
+		writeJSONPair(&sb, key, jsonValue(log.Fields[k]))
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// writeJSONPair writes `"key":value` to sb. Values that cannot be marshalled
+// are written as their fmt.Sprint string.
+func writeJSONPair(sb *strings.Builder, key string, value interface{}) {
+	k, _ := json.Marshal(key)
+	sb.Write(k)
+	sb.WriteByte(':')
+	v, err := json.Marshal(value)
+	if err != nil {
+		v, _ = json.Marshal(fmt.Sprint(value))
+	}
+	sb.Write(v)
+}
+
+// jsonValue converts a field value into the form it should take in JSON, so
+// numbers, booleans and string slices keep their types. Times are RFC3339,
+// durations and errors are strings, non-finite floats are quoted, and any
+// type without a natural JSON form falls back to its fmt.Sprint string.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil, bool, string, []string, json.Number, json.Marshaler,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		return v
+	case float32:
+		return jsonFloat(float64(v))
+	case float64:
+		return jsonFloat(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// jsonFloat returns f, or its string form if JSON cannot represent it.
+func jsonFloat(f float64) interface{} {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Sprint(f)
+	}
+	return f
+}
+
+// ReadJSONLog parses output written with FormatJSON back into entries, in
+// order, for tests and log-processing tools. keys is the `Config.JSONKeys`
+// the output was written with, nil for the default names; for a
+// `Config.Preset` pass the JSONKeys of EffectiveConfig, which include the
+// preset's. The time, "write_time", level and msg keys fill the Log's own
+// fields, Cloud Logging severities are mapped back to levels, and every
+// other key becomes a field, except the "severity" number and the "hash"
+// added by `Config.AuditChain`. Fields written with jsonFieldPrefix get
+// their own names back. Integers are returned as int64, other numbers as
+// float64, and times, durations and errors as the strings they were written
+// as. Blank lines are skipped. On the first malformed line the entries
+// before it are returned with an error naming the line.
+func ReadJSONLog(r io.Reader, keys map[string]string) ([]Log, error) {
+	logs := []Log{}
+	br := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			log, perr := parseJSONLine(line, keys)
+			if perr != nil {
+				return logs, fmt.Errorf("line %d: %w", n, perr)
+			}
+			logs = append(logs, log)
+		}
+		if err == io.EOF {
+			return logs, nil
+		}
+		if err != nil {
+			return logs, err
+		}
+	}
+}
+
+// parseJSONLine decodes one line written by formatJSON with the renaming
+// keys.
+func parseJSONLine(line []byte, keys map[string]string) (Log, error) {
+	var obj map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if err := dec.Decode(&obj); err != nil {
+		return Log{}, err
+	}
+	if obj == nil {
+		return Log{}, errors.New("not a JSON object")
+	}
+	timeKey, levelKey := jsonKeyIn(keys, "time"), jsonKeyIn(keys, "level")
+	msgKey, callerKey := jsonKeyIn(keys, "msg"), jsonKeyIn(keys, callerField)
+	var log Log
+	var err error
+	ts, _ := obj[timeKey].(string)
+	if log.TimeStamp, err = time.Parse(time.RFC3339Nano, ts); err != nil {
+		return Log{}, fmt.Errorf("invalid time %q", ts)
+	}
+	if wt, ok := obj["write_time"].(string); ok {
+		if log.WriteTime, err = time.Parse(time.RFC3339Nano, wt); err != nil {
+			return Log{}, fmt.Errorf("invalid write_time %q", wt)
+		}
+	}
+	level, ok := obj[levelKey].(string)
+	if !ok {
+		return Log{}, errors.New("missing level")
+	}
+	log.Level = readJSONLevel(level)
+	log.Message, _ = obj[msgKey].(string)
+	for k, v := range obj {
+		if k == callerKey {
+			k = callerField
+		} else if jsonStandardKey(keys, k) {
+			continue
+		} else if name, ok := strings.CutPrefix(k, jsonFieldPrefix); ok && jsonStandardKey(keys, name) {
+			k = name
+		}
+		if log.Fields == nil {
+			log.Fields = Fields{}
+		}
+		log.Fields[k] = jsonNumber(v)
+	}
+	return log, nil
+}
+
+// readJSONLevel returns the level for a level name read from JSON: the name
+// itself for a known level, else the level whose Cloud Logging severity it
+// is (see PresetGCP). Other names are returned unchanged.
+func readJSONLevel(name string) string {
+	if _, ok := logLevels()[name]; ok {
+		return name
+	}
+	for level, severity := range gcpSeverities {
+		if severity == name {
+			return level
+		}
+	}
+	return name
+}
+
+// jsonNumber converts a decoded json.Number to int64 when it is an integer
+// and float64 otherwise. Other values are returned unchanged.
+func jsonNumber(v interface{}) interface{} {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
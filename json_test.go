@@ -0,0 +1,266 @@
+// json_test.go
+//
+// # Chronos Logging - JSON Format Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestJSONFieldTypes unmarshals a JSON line and asserts numbers, booleans,
+// and times keep their types, with unknown types rendered by fmt.Sprint.
+func TestJSONFieldTypes(t *testing.T) {
+	cfg := getConfig()
+	cfg.Format = FormatJSON
+	l := newLogging(cfg, logLevels()[INFO])
+
+	when := time.Date(2025, 3, 1, 10, 4, 5, 0, time.UTC)
+	line := l.formatFile(Log{TimeStamp: when, Level: INFO, Message: "typed", Fields: Fields{
+		"count":   42,
+		"big":     uint64(1 << 40),
+		"ratio":   0.25,
+		"ok":      true,
+		"when":    when,
+		"err":     errors.New("boom"),
+		"nan":     math.NaN(),
+		"nothing": nil,
+		"pair":    struct{ A, B int }{1, 2},
+	}})
+
+	dec := json.NewDecoder(bytes.NewReader([]byte(line)))
+	dec.UseNumber()
+	var got map[string]interface{}
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", line, err)
+	}
+
+	for key, want := range map[string]string{"count": "42", "big": "1099511627776", "ratio": "0.25"} {
+		n, ok := got[key].(json.Number)
+		if !ok || n.String() != want {
+			t.Errorf("%s: expected number %s, got %T %v", key, want, got[key], got[key])
+		}
+	}
+	if got["ok"] != true {
+		t.Errorf("expected boolean true, got %T %v", got["ok"], got["ok"])
+	}
+	if got["when"] != "2025-03-01T10:04:05Z" {
+		t.Errorf("expected RFC3339 time, got %v", got["when"])
+	}
+	if got["err"] != "boom" || got["nan"] != "NaN" || got["nothing"] != nil {
+		t.Errorf("unexpected values err=%v nan=%v nothing=%v", got["err"], got["nan"], got["nothing"])
+	}
+	if got["pair"] != "{1 2}" {
+		t.Errorf("expected fmt.Sprint fallback, got %v", got["pair"])
+	}
+}
+
+// TestIncludeSeverityNumber asserts the numeric severity of every level
+// matches logLevels, as a JSON number and as a text column.
+func TestIncludeSeverityNumber(t *testing.T) {
+	cfg := getConfig()
+	cfg.Format = FormatJSON
+	cfg.IncludeSeverityNumber = true
+	l := newLogging(cfg, logLevels()[INFO])
+	text := newLogging(&Config{IncludeSeverityNumber: true}, logLevels()[INFO])
+
+	for i, level := range []string{DEBUG, INFO, WARN, ERROR, FATAL} {
+		log := Log{TimeStamp: time.Now(), Level: level, Message: "m"}
+		var got struct {
+			Level    string
+			Severity json.Number
+		}
+		line := l.formatFile(log)
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		want := strconv.Itoa(i + 1)
+		if got.Level != level || got.Severity.String() != want || !strings.Contains(line, `"severity":`+want) {
+			t.Errorf("%s: expected severity %s, got %q", level, want, line)
+		}
+		if line := text.formatLine(log); !strings.Contains(line, "\t"+level+"\t"+want+"\tm") {
+			t.Errorf("%s: expected severity column %s, got %q", level, want, line)
+		}
+	}
+}
+
+// TestFileExtension asserts JSON files default to .jsonl, text files to
+// .log, and that FileExtension overrides both.
+func TestFileExtension(t *testing.T) {
+	cases := []struct {
+		format LogFormat
+		ext    string
+		want   string
+	}{
+		{FormatJSON, "", ".jsonl"},
+		{FormatText, "", ".log"},
+		{FormatJSON, "txt", ".txt"},
+		{FormatText, ".out", ".out"},
+	}
+	for _, c := range cases {
+		Stop()
+		cfg := getConfig()
+		cfg.Location = t.TempDir()
+		cfg.Format = c.format
+		cfg.FileExtension = c.ext
+		cfg.SyncForTest = true
+		if err := Init(cfg); err != nil {
+			t.Fatalf("Init failed: %v", err)
+		}
+		Info("named")
+		path := PathFor(time.Now())
+		Stop()
+		if filepath.Ext(path) != c.want {
+			t.Errorf("%s with %q: expected %s file, got %s", c.format, c.ext, c.want, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s with %q: %v", c.format, c.ext, err)
+		}
+	}
+}
+
+// TestJSONKeys renames msg and level and asserts the JSON uses the new keys
+// while time keeps its default, and that unknown names are rejected.
+func TestJSONKeys(t *testing.T) {
+	cfg := getConfig()
+	cfg.Format = FormatJSON
+	cfg.JSONKeys = map[string]string{"msg": "message", "level": "severity", "caller": "source"}
+	if _, err := resolveConfig(cfg); err != nil {
+		t.Fatalf("resolveConfig failed: %v", err)
+	}
+	l := newLogging(cfg, logLevels()[INFO])
+	log := Log{TimeStamp: time.Now(), Level: WARN, Message: "disk low", Fields: Fields{callerField: "app/main.go:7"}}
+	line := l.formatFile(log)
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", line, err)
+	}
+	if got["message"] != "disk low" || got["severity"] != WARN || got["source"] != "app/main.go:7" {
+		t.Errorf("expected renamed keys, got %q", line)
+	}
+	for _, key := range []string{"msg", "level", "caller"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("default key %q still present in %q", key, line)
+		}
+	}
+	if _, ok := got["time"]; !ok {
+		t.Errorf("expected default time key in %q", line)
+	}
+
+	bad := getConfig()
+	bad.JSONKeys = map[string]string{"message": "msg"}
+	if _, err := resolveConfig(bad); err == nil {
+		t.Error("expected an error for an unknown JSONKeys name")
+	}
+}
+
+// TestReadJSONLog writes JSON entries, including fields named like standard
+// keys, reads them back and asserts they match, then checks blank lines are
+// skipped and a malformed line stops the read with the entries before it.
+func TestReadJSONLog(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.Format = FormatJSON
+	cfg.Level = DEBUG
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	want := []Log{
+		{Level: INFO, Message: "started"},
+		{Level: WARN, Message: "slow \"query\"\nsecond line", Fields: Fields{"ms": int64(1500), "ratio": 0.25, "ok": false, "table": "users"}},
+		{Level: DEBUG, Message: "", Fields: Fields{"n": int64(-3), "level": "shadow", "msg": "shadow"}},
+	}
+	base := time.Date(2025, 3, 1, 12, 0, 0, 123456789, time.UTC)
+	for i := range want {
+		want[i].TimeStamp = base.Add(time.Duration(i) * time.Millisecond)
+		logger.Load().addLog(want[i])
+	}
+	path := PathFor(base)
+	Stop()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadJSONLog(bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("ReadJSONLog failed: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if !got[i].TimeStamp.Equal(want[i].TimeStamp) || got[i].Level != want[i].Level || got[i].Message != want[i].Message || !reflect.DeepEqual(got[i].Fields, want[i].Fields) {
+			t.Errorf("entry %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	input := bytes.Join([][]byte{lines[0], []byte("\n  \n"), lines[1], []byte("{not json\n"), lines[2]}, nil)
+	got, err = ReadJSONLog(bytes.NewReader(input), nil)
+	if err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Errorf("expected an error naming line 5, got %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected the 2 entries before the malformed line, got %d", len(got))
+	}
+}
+
+// TestReadJSONLogKeys reads back output written with PresetGCP and a
+// renamed caller key, and asserts levels, messages and fields survive.
+func TestReadJSONLogKeys(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.Preset = PresetGCP
+	cfg.JSONKeys = map[string]string{callerField: "source"}
+	cfg.IncludeCaller = true
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	keys := EffectiveConfig().JSONKeys
+	path := PathFor(time.Now())
+	WithFields(Fields{"severity": "high", "source": "db"}).Warn("disk low")
+	Fatal("gone")
+	Stop()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadJSONLog(bytes.NewReader(data), keys)
+	if err != nil {
+		t.Fatalf("ReadJSONLog failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(got))
+	}
+	if got[0].Level != WARN || got[0].Message != "disk low" || got[1].Level != FATAL {
+		t.Errorf("unexpected entries %+v", got)
+	}
+	if got[0].Fields["severity"] != "high" || got[0].Fields["source"] != "db" {
+		t.Errorf("expected colliding fields back under their own names, got %v", got[0].Fields)
+	}
+	if caller, _ := got[0].Fields[callerField].(string); !strings.Contains(caller, "json_test.go") {
+		t.Errorf("expected the caller read from the renamed key, got %v", got[0].Fields)
+	}
+}
@@ -17,35 +17,294 @@
 //
 // Severity ordering (low -> high):
 //
-//	INFO(1) < DEBUG(2) < WARN(3) < ERROR(4) < FATAL(5)
+//	DEBUG(1) < INFO(2) < WARN(3) < ERROR(4) < FATAL(5) < PANIC(6)
 //
 // Note: Higher numbers are treated as more severe. Messages are emitted when
 // their severity is greater than or equal to the configured threshold.
+// Internally levels are compared by rank, which is ten times the severity
+// for the built-in levels, so levels added with RegisterLevel can sit
+// between them.
 package chronos
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
 // Level names used throughout the logger and configuration.
 //
 // These constants are the canonical string representations written to the
-// console and log files, and accepted via `Config.Level`.
+// console and log files, and accepted via `Config.Level`. Level names given in
+// configuration are matched case-insensitively, and the aliases WARNING, ERR,
+// CRITICAL, and CRIT are accepted too.
 const (
 	INFO  = "INFO"
 	DEBUG = "DEBUG"
 	WARN  = "WARN"
 	ERROR = "ERROR"
 	FATAL = "FATAL"
+	PANIC = "PANIC"
 )
 
-// logLevels maps level names to their internal severity for filtering.
+// levelTable is a snapshot of the known levels. It is never modified once
+// published: RegisterLevel stores a new copy, so the hot path reads it
+// without locking.
+type levelTable struct {
+	ranks  map[string]int
+	colors map[string]string
+}
+
+// currentLevels holds the levelTable in use.
+var currentLevels atomic.Pointer[levelTable]
+
+func init() {
+	currentLevels.Store(&levelTable{
+		ranks: map[string]int{
+			DEBUG: levelRank(int(LevelDebug)),
+			INFO:  levelRank(int(LevelInfo)),
+			WARN:  levelRank(int(LevelWarn)),
+			ERROR: levelRank(int(LevelError)),
+			FATAL: levelRank(int(LevelFatal)),
+			PANIC: levelRank(int(LevelPanic)),
+		},
+		colors: map[string]string{},
+	})
+}
+
+// logLevels maps level names to their rank for filtering. The map must not
+// be modified.
 //
 // The values are intentionally monotonic increasing to reflect severity.
 // They are used in comparisons like:
-//   if logLevels[log.Level] < l.logLevel { return }
+//   if logLevels()[log.Level] < int(l.logLevel.Load()) { return }
 // so any message with a severity lower than the configured threshold is
 // dropped before printing or enqueuing for file persistence.
-var logLevels map[string]int = map[string]int{
-	DEBUG: 1,
-	INFO:  2,
-	WARN:  3,
-	ERROR: 4,
-	FATAL: 5,
+func logLevels() map[string]int {
+	return currentLevels.Load().ranks
+}
+
+// levelRank converts a severity to the rank levels are compared by. The
+// built-in severities 1 to 6 rank 10 to 60, leaving room for registered
+// levels between them; registered severities are their own rank.
+func levelRank(severity int) int {
+	if severity >= int(LevelDebug) && severity <= int(LevelPanic) {
+		return severity * 10
+	}
+	return severity
+}
+
+// levelSeverity converts a rank back to the severity it came from.
+func levelSeverity(rank int) int {
+	if rank%10 == 0 && rank >= levelRank(int(LevelDebug)) && rank <= levelRank(int(LevelPanic)) {
+		return rank / 10
+	}
+	return rank
+}
+
+// Level is a typed log level. Its value is the level's severity; String
+// returns the canonical name, and it marshals to and from JSON as that name
+// (numbers are accepted when unmarshalling too).
+type Level int
+
+// Typed equivalents of the level name constants.
+const (
+	LevelDebug Level = 1
+	LevelInfo  Level = 2
+	LevelWarn  Level = 3
+	LevelError Level = 4
+	LevelFatal Level = 5
+	LevelPanic Level = 6
+)
+
+// String returns the canonical name of the level, or Level(n) if no level
+// has that severity.
+func (lv Level) String() string {
+	if name, ok := levelForSeverity(int(lv)); ok {
+		return name
+	}
+	return fmt.Sprintf("Level(%d)", int(lv))
+}
+
+// ParseLevel resolves a level name case-insensitively, accepting the same
+// aliases as `Config.Level`.
+func ParseLevel(name string) (Level, error) {
+	canonical, ok := parseLevel(name)
+	if !ok {
+		return 0, fmt.Errorf("invalid log level: %s", name)
+	}
+	return Level(levelSeverity(logLevels()[canonical])), nil
+}
+
+// MarshalJSON encodes the level as its name.
+func (lv Level) MarshalJSON() ([]byte, error) {
+	if _, ok := levelForSeverity(int(lv)); !ok {
+		return nil, fmt.Errorf("invalid log level: %d", int(lv))
+	}
+	return json.Marshal(lv.String())
+}
+
+// UnmarshalJSON decodes a level name or a numeric severity.
+func (lv *Level) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		parsed, err := ParseLevel(name)
+		if err != nil {
+			return err
+		}
+		*lv = parsed
+		return nil
+	}
+	var severity int
+	if err := json.Unmarshal(data, &severity); err != nil {
+		return fmt.Errorf("invalid log level: %s", data)
+	}
+	if _, ok := levelForSeverity(severity); !ok {
+		return fmt.Errorf("invalid log level: %d", severity)
+	}
+	*lv = Level(severity)
+	return nil
+}
+
+// levelAliases maps common alternative level names to their canonical form.
+var levelAliases = map[string]string{
+	"WARNING":  WARN,
+	"ERR":      ERROR,
+	"CRITICAL": FATAL,
+	"CRIT":     FATAL,
+}
+
+// parseLevel resolves a level name case-insensitively, accepting the aliases
+// in levelAliases, and returns the canonical level constant.
+func parseLevel(name string) (string, bool) {
+	level := strings.ToUpper(strings.TrimSpace(name))
+	if alias, ok := levelAliases[level]; ok {
+		level = alias
+	}
+	if _, ok := logLevels()[level]; !ok {
+		return "", false
+	}
+	return level, true
+}
+
+// SetLevel changes the minimum level of the running logger. The change is
+// process-wide and takes effect for entries logged after it returns.
+func SetLevel(level string) error {
+	canonical, ok := parseLevel(level)
+	if !ok {
+		return fmt.Errorf("invalid log level: %s", level)
+	}
+	rank := logLevels()[canonical]
+	mu.Lock()
+	defer mu.Unlock()
+	l := logger.Load()
+	if l == nil {
+		return errors.New("logger not initialized")
+	}
+	l.logLevel.Store(int32(rank))
+	return nil
+}
+
+// GetLevel returns the minimum level of the running logger, or an empty
+// string when the logger is not initialized.
+func GetLevel() string {
+	mu.Lock()
+	defer mu.Unlock()
+	l := logger.Load()
+	if l == nil {
+		return ""
+	}
+	level, _ := levelForRank(int(l.logLevel.Load()))
+	return level
+}
+
+// levelColors holds the console colors of levels added with RegisterLevel.
+// The map must not be modified.
+func levelColors() map[string]string {
+	return currentLevels.Load().colors
+}
+
+// RegisterLevel adds a custom level, such as AUDIT or SECURITY, with the
+// given severity and console color (an ANSI escape sequence such as
+// "\033[36m"; empty leaves the line uncolored). Registered severities are
+// on a scale ten times finer than the built-in ones, where DEBUG is 10 and
+// PANIC 60, so 35 sits between WARN and ERROR. Entries are logged at custom
+// levels with LogAt and LogAtf, and the name is accepted by `Config.Level` and
+// SetLevel like a built-in one.
+//
+// Levels can be registered at any time, also while entries are being logged;
+// register them before Init to use them in `Config.Level`. The name must be
+// new, and the severity must be between 7 and 255 and not used by another
+// level.
+func RegisterLevel(name string, severity int, color string) error {
+	level := strings.ToUpper(strings.TrimSpace(name))
+	if level == "" || strings.ContainsAny(level, " \t\r\n") {
+		return fmt.Errorf("invalid level name: %q", name)
+	}
+	if severity <= int(LevelPanic) || severity > 255 {
+		return fmt.Errorf("invalid severity for level %s: %d", level, severity)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	current := currentLevels.Load()
+	if _, ok := current.ranks[level]; ok {
+		return fmt.Errorf("level already registered: %s", level)
+	}
+	if _, ok := levelAliases[level]; ok {
+		return fmt.Errorf("level already registered: %s", level)
+	}
+	if existing, ok := levelForRank(severity); ok {
+		return fmt.Errorf("severity %d already used by level %s", severity, existing)
+	}
+	next := &levelTable{
+		ranks:  make(map[string]int, len(current.ranks)+1),
+		colors: make(map[string]string, len(current.colors)+1),
+	}
+	for k, v := range current.ranks {
+		next.ranks[k] = v
+	}
+	for k, v := range current.colors {
+		next.colors[k] = v
+	}
+	next.ranks[level] = severity
+	next.colors[level] = color
+	currentLevels.Store(next)
+	return nil
+}
+
+// levelForSeverity returns the level name with the given severity.
+func levelForSeverity(severity int) (string, bool) {
+	rank := levelRank(severity)
+	if levelSeverity(rank) != severity {
+		return "", false
+	}
+	return levelForRank(rank)
+}
+
+// levelForRank returns the level name with the given rank.
+func levelForRank(rank int) (string, bool) {
+	for name, r := range logLevels() {
+		if r == rank {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// WithLevel runs fn with the minimum level set to level, restoring the
+// previous level afterwards, even if fn panics. It is useful for wrapping a
+// suspect operation in DEBUG without changing verbosity everywhere.
+//
+// The level is process-wide: entries logged by other goroutines while fn runs
+// are filtered by the temporary level too.
+func WithLevel(level string, fn func()) error {
+	previous := GetLevel()
+	if err := SetLevel(level); err != nil {
+		return err
+	}
+	defer SetLevel(previous)
+	fn()
+	return nil
 }
@@ -0,0 +1,331 @@
+// levels_test.go
+//
+// # Chronos Logging - Log Level Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestPanicWritesAndPanics asserts Panic persists the entry before panicking
+// with the message.
+func TestPanicWritesAndPanics(t *testing.T) {
+	Stop()
+
+	tempDir, err := os.MkdirTemp("", "panic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+	path := logger.Load().filePathFor(time.Now())
+
+	var recovered interface{}
+	func() {
+		defer func() { recovered = recover() }()
+		Panicf("unrecoverable %d", 42)
+	}()
+
+	if recovered != "unrecoverable 42" {
+		t.Fatalf("expected panic with message, got %v", recovered)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read log file: %v", err)
+	}
+	if !strings.Contains(string(content), "PANIC\tunrecoverable 42") {
+		t.Errorf("expected PANIC entry in file, got %q", content)
+	}
+}
+
+// TestPanicSeverity ensures PANIC sits above FATAL.
+func TestPanicSeverity(t *testing.T) {
+	if logLevels()[PANIC] <= logLevels()[FATAL] {
+		t.Errorf("expected PANIC above FATAL, got %d <= %d", logLevels()[PANIC], logLevels()[FATAL])
+	}
+}
+
+// TestWithLevel asserts DEBUG entries are captured inside the scope only and
+// the previous level is restored even when the scope panics.
+func TestWithLevel(t *testing.T) {
+	Stop()
+
+	tempDir, err := os.MkdirTemp("", "withlevel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	path := logger.Load().filePathFor(time.Now())
+
+	Debug("before scope")
+	if err := WithLevel(DEBUG, func() { Debug("inside scope") }); err != nil {
+		t.Fatalf("WithLevel failed: %v", err)
+	}
+	Debug("after scope")
+
+	func() {
+		defer func() { recover() }()
+		WithLevel(DEBUG, func() { panic("boom") })
+	}()
+	if got := GetLevel(); got != INFO {
+		t.Errorf("expected level restored to INFO after panic, got %s", got)
+	}
+	if err := WithLevel("VERBOSE", func() {}); err == nil {
+		t.Error("expected error for invalid level")
+	}
+	logger.Load().flush()
+	Stop()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read log file: %v", err)
+	}
+	if !strings.Contains(string(content), "inside scope") {
+		t.Errorf("expected DEBUG entry inside scope, got %q", content)
+	}
+	if strings.Contains(string(content), "before scope") || strings.Contains(string(content), "after scope") {
+		t.Errorf("expected DEBUG entries outside scope to be filtered, got %q", content)
+	}
+}
+
+// TestInitLevelAliases asserts lowercase and aliased level names are accepted
+// and normalized to the canonical constant.
+func TestInitLevelAliases(t *testing.T) {
+	cases := map[string]string{
+		"info":     INFO,
+		"Warning":  WARN,
+		"warn":     WARN,
+		"ERR":      ERROR,
+		"critical": FATAL,
+		"Crit":     FATAL,
+		"debug":    DEBUG,
+	}
+	for input, want := range cases {
+		Stop()
+		cfg := getConfig()
+		cfg.Level = input
+		if err := Init(cfg); err != nil {
+			t.Errorf("Init(%q) failed: %v", input, err)
+			continue
+		}
+		if cfg.Level != want {
+			t.Errorf("Init(%q): expected level %s, got %s", input, want, cfg.Level)
+		}
+		if got := GetLevel(); got != want {
+			t.Errorf("Init(%q): expected active level %s, got %s", input, want, got)
+		}
+		Stop()
+	}
+
+	cfg := getConfig()
+	cfg.Level = "verbose"
+	if err := Init(cfg); err == nil {
+		Stop()
+		t.Error("expected unknown level to be rejected")
+	}
+}
+
+// TestLevelType covers parsing, stringifying, and JSON round-trips of Level.
+func TestLevelType(t *testing.T) {
+	for name, want := range map[string]Level{"debug": LevelDebug, "INFO": LevelInfo, "Warning": LevelWarn, "crit": LevelFatal, "panic": LevelPanic} {
+		got, err := ParseLevel(name)
+		if err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %v, %v; expected %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("INFORMATION"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+
+	if LevelError.String() != ERROR || Level(7).String() != "Level(7)" || Level(40).String() != "Level(40)" {
+		t.Errorf("unexpected names %q, %q, %q", LevelError.String(), Level(7).String(), Level(40).String())
+	}
+
+	data, err := json.Marshal(LevelWarn)
+	if err != nil || string(data) != `"WARN"` {
+		t.Fatalf("unexpected JSON %s, %v", data, err)
+	}
+	var lv Level
+	if err := json.Unmarshal(data, &lv); err != nil || lv != LevelWarn {
+		t.Errorf("round trip gave %v, %v", lv, err)
+	}
+	if err := json.Unmarshal([]byte("4"), &lv); err != nil || lv != LevelError {
+		t.Errorf("numeric severity gave %v, %v", lv, err)
+	}
+	if err := json.Unmarshal([]byte(`"INFORMATION"`), &lv); err == nil {
+		t.Error("expected error unmarshalling unknown level")
+	}
+}
+
+// TestConfigLevelJSON asserts Config accepts a level name or severity.
+func TestConfigLevelJSON(t *testing.T) {
+	for input, want := range map[string]string{
+		`{"app_name":"test","level":"warning"}`: WARN,
+		`{"app_name":"test","level":1}`:         DEBUG,
+		`{"app_name":"test"}`:                   "",
+	} {
+		var cfg Config
+		if err := json.Unmarshal([]byte(input), &cfg); err != nil {
+			t.Errorf("%s: %v", input, err)
+			continue
+		}
+		if cfg.AppName != "test" || cfg.Level != want {
+			t.Errorf("%s: expected level %q, got %+v", input, want, cfg)
+		}
+	}
+}
+
+// TestRegisterLevel registers AUDIT between WARN and ERROR and asserts it is
+// filtered by severity and rendered with its name and color.
+func TestRegisterLevel(t *testing.T) {
+	Stop()
+	const cyan = "\033[36m"
+	defer currentLevels.Store(currentLevels.Load())
+	if err := RegisterLevel("audit", 35, cyan); err != nil {
+		t.Fatalf("RegisterLevel failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		severity int
+	}{
+		{"audit", 36},
+		{"warning", 36},
+		{"INFO", 36},
+		{"SECURITY", 30},
+		{"SECURITY", 3},
+		{"SECURITY", 0},
+		{"", 36},
+	} {
+		if err := RegisterLevel(tc.name, tc.severity, ""); err == nil {
+			t.Errorf("expected RegisterLevel(%q, %d) to fail", tc.name, tc.severity)
+		}
+	}
+
+	for _, tc := range []struct {
+		threshold string
+		want      bool
+	}{
+		{WARN, true},
+		{"audit", true},
+		{ERROR, false},
+	} {
+		Stop()
+		fs := newMemFS()
+		cfg := getConfig()
+		cfg.Level = tc.threshold
+		level, err := resolveConfig(cfg)
+		if err != nil {
+			t.Fatalf("resolveConfig(%s) failed: %v", tc.threshold, err)
+		}
+		var console syncBuffer
+		l := newLogging(cfg, level)
+		l.opener = fs.open
+		l.console = newConsoleWriter(&console, false)
+		logger.Store(l)
+		done := make(chan struct{})
+		go func() {
+			l.start()
+			close(done)
+		}()
+
+		now := time.Now()
+		LogAtf("Audit", "user %s signed in", "alice")
+		Stop()
+		<-done
+
+		f := fs.file(l.filePathFor(now))
+		if !tc.want {
+			if f != nil || console.String() != "" {
+				t.Errorf("threshold %s: expected AUDIT to be filtered", tc.threshold)
+			}
+			continue
+		}
+		if f == nil || !strings.Contains(f.String(), "\tAUDIT\tuser alice signed in\n") {
+			t.Errorf("threshold %s: unexpected file content", tc.threshold)
+		}
+		if !strings.HasPrefix(console.String(), cyan) {
+			t.Errorf("threshold %s: expected cyan console line, got %q", tc.threshold, console.String())
+		}
+	}
+
+	if err := Init(getConfig()); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+	if err := RegisterLevel("SECURITY", 45, ""); err != nil {
+		t.Errorf("expected RegisterLevel to work after Init, got %v", err)
+	}
+	if !IsEnabled("SECURITY") {
+		t.Error("expected a level registered after Init to be enabled")
+	}
+}
+
+// TestRegisterLevelConcurrent registers levels while other goroutines log
+// and parse level names; run with -race it asserts the level table is
+// never read while being written.
+func TestRegisterLevelConcurrent(t *testing.T) {
+	Stop()
+	defer currentLevels.Store(currentLevels.Load())
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.ErrorHandler = func(error) {}
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				Debug("filtered")
+				LogAt("LEVEL7", "maybe registered")
+				ParseLevel("LEVEL3")
+				_ = Level(33).String()
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		if err := RegisterLevel(fmt.Sprintf("LEVEL%d", i), 100+i, ""); err != nil {
+			t.Errorf("RegisterLevel failed: %v", err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+	if _, err := ParseLevel("LEVEL9"); err != nil {
+		t.Errorf("expected LEVEL9 registered, got %v", err)
+	}
+}
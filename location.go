@@ -0,0 +1,104 @@
+// location.go
+//
+// # Chronos Logging - Log Locations
+//
+// Derives the default log directory from AppName and, with
+// `Config.FallbackLocation`, picks a user-writable directory when that
+// default cannot be written (e.g. /var/log for a non-root process). A
+// Location reached through symlinks is resolved at Init, so the writer,
+// retention and snapshots all work on the real directory.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// defaultLocation returns the OS-specific default log directory for app. It
+// is a variable so tests can point it somewhere unwritable.
+var defaultLocation = func(app string) string {
+	if runtime.GOOS == "windows" {
+		return fmt.Sprintf("C:\\ProgramData\\%s\\logs", app)
+	}
+	return fmt.Sprintf("/var/log/%s", app)
+}
+
+// fallbackLocation returns a user-writable log directory for app:
+// $XDG_STATE_HOME/<app>/logs, or its default ~/.local/state/<app>/logs
+// (%LocalAppData%\<app>\logs on Windows), or <tmp>/<app>/logs when no home
+// directory is available. The first one that is writable is used.
+func fallbackLocation(app string) (string, error) {
+	var candidates []string
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("LocalAppData"); dir != "" {
+			candidates = append(candidates, filepath.Join(dir, app, "logs"))
+		}
+	} else {
+		if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+			candidates = append(candidates, filepath.Join(dir, app, "logs"))
+		}
+		if home, err := os.UserHomeDir(); err == nil {
+			candidates = append(candidates, filepath.Join(home, ".local", "state", app, "logs"))
+		}
+	}
+	candidates = append(candidates, filepath.Join(os.TempDir(), app, "logs"))
+	for _, dir := range candidates {
+		if dirWritable(dir) {
+			return dir, nil
+		}
+	}
+	return "", errors.New("no writable fallback log location")
+}
+
+// dirWritable reports whether dir exists or can be created, and accepts new
+// files. The probe file is removed again.
+func dirWritable(dir string) bool {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false
+	}
+	f, err := os.CreateTemp(dir, ".chronos-probe-*")
+	if err != nil {
+		return false
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return true
+}
+
+// resolveLocation returns dir with every symlink resolved. Components that
+// do not exist yet are kept as they are, below the resolved part that does.
+// A symlink whose target is missing is an error rather than a directory to
+// be created.
+func resolveLocation(dir string) (string, error) {
+	if fi, err := os.Lstat(dir); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+		if _, err := os.Stat(dir); err != nil {
+			target, _ := os.Readlink(dir)
+			return "", fmt.Errorf("log location %s is a dangling symlink to %s: %w", dir, target, err)
+		}
+	}
+	real, err := filepath.EvalSymlinks(dir)
+	if err == nil {
+		return real, nil
+	}
+	if !os.IsNotExist(err) {
+		return dir, nil
+	}
+	parent := filepath.Dir(dir)
+	if parent == dir {
+		return dir, nil
+	}
+	real, err = resolveLocation(parent)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(real, filepath.Base(dir)), nil
+}
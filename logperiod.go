@@ -40,3 +40,12 @@ const (
     LogPeriodMonth LogPeriod = "Month"
     LogPeriodYear  LogPeriod = "Year"
 )
+
+// valid reports whether p is one of the supported rotation cadences.
+func (p LogPeriod) valid() bool {
+    switch p {
+    case LogPeriodHour, LogPeriodDay, LogPeriodWeek, LogPeriodMonth, LogPeriodYear:
+        return true
+    }
+    return false
+}
@@ -15,22 +15,47 @@
 package chronos
 
 import (
+	"bufio"
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
-	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 )
 
-// Log represents a single log entry with timestamp, level, and message.
+// Log represents a single log entry with timestamp, level, message, and any
+// structured fields attached by the caller or by middleware.
 type Log struct {
 	TimeStamp time.Time
 	Level     string
 	Message   string
+	Fields    Fields
+
+	// WriteTime is when the writer persisted the entry. It is only set when
+	// `Config.IncludeWriteTime` is enabled.
+	WriteTime time.Time
+
+	// retention is the entry's retention class (see InfoRetention), or
+	// empty for untagged entries.
+	retention string
+
+	// done, when set, receives the writer's result once the entry has been
+	// processed. An entry with done set and no Level is a flush barrier.
+	done chan error
+
+	// control, on a barrier, is run by the writer in queue order and its
+	// error sent to done (see Snapshot).
+	control func() error
+
+	// replayed marks an entry logged before Init (see preinit.go), whose
+	// caller can no longer be determined.
+	replayed bool
 }
 
 // Logging is the logger instance handling level filtering and async writes.
@@ -39,65 +64,548 @@ type Logging struct {
 	config   *Config
 	path     string
 	logChan  chan Log
-	logLevel int
+	logLevel atomic.Int32
+	quit     chan struct{}
+	reopen   chan struct{}
+	console  *consoleWriter
+	// noColor disables console colors, see console.go.
+	noColor  bool
+	template *filenameTemplate
+	sampler  *sampler
+	// loc is `Config.Timezone`, or nil for the local zone.
+	loc   *time.Location
+	dedup *deduper
+	hooks *hookPool
+	pacer *pacer
+	// csvColumns are the field columns of CSV files, see csv.go.
+	csvColumns []string
+
+	// Per-module minimum levels, see module.go.
+	moduleLevels map[string]int
+	moduleFloor  int
+
+	// Writer state, owned by the start() goroutine.
+	opener  opener
+	handles map[string]*logHandle
+	// announced holds the last file passed to `Config.OnFileCreate` per
+	// stream key.
+	announced map[string]string
+	// lastPaths holds the last file opened per stream key, kept after its
+	// handle closes, so rotations are seen across idle closes.
+	lastPaths map[string]string
+	// files is the file writer as a Sink, see sink.go.
+	files *FileSink
+	// sinks feed `Config.CustomSinks`, each from its own goroutine.
+	sinks     []*customSink
+	lastWrite time.Time
+	latest    time.Time
+	fifo      string
+	fifoDown  bool
+	batching  bool
+	// pendingEntries counts entries buffered since the last flush.
+	pendingEntries int
+	tee            *teeWriter
+	cloudwatch     *cloudWatchSink
+	remote         *remoteDispatcher
+	// file is the caller-owned file written instead of rotating files, see
+	// NewWithFile. It is never closed by the logger.
+	file *os.File
+
+	remoteDropped atomic.Uint64
+	sinkDropped   atomic.Uint64
+
+	// compressing tracks background compression jobs, see compress.go.
+	compressing sync.WaitGroup
+	// compressor compresses a rotated file; compressSlots, when non-nil,
+	// bounds the jobs running at once (`Config.CompressConcurrency`).
+	compressor    func(path string, level int) error
+	compressSlots chan struct{}
+
+	// unknownPeriod is set once a filename falls back to a daily date for an
+	// unknown `Config.FilePeriod`; periodWarned, owned by the writer, records
+	// that the WARN was logged.
+	unknownPeriod atomic.Bool
+	periodWarned  bool
+
+	// inlineMu serializes writer state with `Config.SyncForTest`, where
+	// callers write inline instead of start() (see inline.go).
+	inlineMu sync.Mutex
+
+	// sendMu guards logChan: producers hold it for reading while they send
+	// and stop() takes it to close the queue, so an entry logged during
+	// shutdown is discarded rather than sent on a closed channel. stopped
+	// is also checked by inline writes.
+	sendMu  sync.RWMutex
+	stopped atomic.Bool
+
+	// Overflow state, see overflow.go. droppedReported is owned by the
+	// writer goroutine.
+	dropped         atomic.Uint64
+	dropping        atomic.Bool
+	droppedReported uint64
+	// dropThreshold is the total drop count that triggers the next drop
+	// warning. Owned by the writer goroutine.
+	dropThreshold uint64
+
+	// Fields added to every entry, see fields.go.
+	defaultsMu sync.RWMutex
+	defaults   Fields
+
+	// Quiet-until-error state, see quiet.go.
+	quietMu    sync.Mutex
+	quietBuf   []Log
+	quietEnded bool
+
+	// Pause state, see pause.go. pauseLost counts entries dropped while
+	// paused; resuming is set while Resume queues the held entries, and
+	// resumeMu serializes Resume calls.
+	pauseMu   sync.Mutex
+	resumeMu  sync.Mutex
+	paused    atomic.Bool
+	resuming  bool
+	pauseBuf  []Log
+	pauseLost int
 }
 
-var logger *Logging
+var logger atomic.Pointer[Logging]
 var mu sync.Mutex
 var externalHandler func(time.Time, string, string)
 
+// clock returns the current time. It is a variable so tests can control time.
+var clock = time.Now
+
 // newLogging creates a new logger writing daily files to the given path and
 // filtering below the provided log level.
 func newLogging(cfg *Config, logLevel int) *Logging {
-	os.MkdirAll(cfg.Location, 0755)
+	fifo := cfg.FIFO
+	if fifo == "" && isFIFO(cfg.Location) {
+		fifo = cfg.Location
+	}
+	if fifo == "" && cfg.Location != "" && cfg.FS == nil {
+		os.MkdirAll(cfg.Location, 0755)
+		for _, dir := range cfg.Locations {
+			os.MkdirAll(dir, 0755)
+		}
+	}
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	consoleOut := cfg.ConsoleWriter
+	if consoleOut == nil {
+		consoleOut = os.Stdout
+	}
 	l := &Logging{
-		config:   cfg,
-		path:     cfg.Location,
-		logChan:  make(chan Log, 10000),
-		logLevel: logLevel,
+		config:  cfg,
+		path:    cfg.Location,
+		logChan: make(chan Log, bufferSize),
+		opener:  openOSFile,
+		quit:    make(chan struct{}),
+		reopen:  make(chan struct{}, 1),
+		console: newConsoleWriter(consoleOut, cfg.ConsoleBuffered),
+		fifo:    fifo,
+	}
+	l.files = &FileSink{l: l}
+	_, l.noColor = os.LookupEnv(noColorEnv)
+	if cfg.FS != nil {
+		l.opener = cfg.FS.OpenFile
+	}
+	if cfg.FilenameTemplate != "" {
+		l.template, _ = parseFilenameTemplate(cfg.FilenameTemplate)
+	}
+	if len(cfg.DefaultFields) > 0 {
+		l.defaults = make(Fields, len(cfg.DefaultFields))
+		for k, v := range cfg.DefaultFields {
+			l.defaults[k] = v
+		}
+	}
+	if cfg.ConsoleErrWriter != nil {
+		l.console.errW = bufio.NewWriter(cfg.ConsoleErrWriter)
+	}
+	l.console.report = l.reportError
+	if cfg.GuardConsole {
+		l.console.guard = &ConsoleMutex
+	}
+	l.moduleLevels, l.moduleFloor = newModuleLevels(cfg.ModuleLevels)
+	if cfg.Sampling != nil || len(cfg.LevelSampling) > 0 {
+		l.sampler = newSampler(cfg.Sampling, cfg.LevelSampling)
+	}
+	if cfg.Timezone != "" {
+		l.loc, _ = time.LoadLocation(cfg.Timezone)
+	}
+	if cfg.DedupWindow > 0 {
+		l.dedup = newDeduper(cfg.DedupWindow)
+	}
+	if cfg.SmoothRate > 0 {
+		l.pacer = newPacer(cfg.SmoothRate)
 	}
+	l.compressor = compressFile
+	if cfg.CompressConcurrency > 0 {
+		l.compressSlots = make(chan struct{}, cfg.CompressConcurrency)
+	}
+	if cfg.Format == FormatCSV {
+		l.csvColumns = csvColumns(cfg)
+	}
+	if cfg.HookWorkers > 0 {
+		l.hooks = newHookPool(cfg.HookWorkers)
+	}
+	l.logLevel.Store(int32(logLevel))
 	return l
 }
 
 // Init initializes the package-level logger from configuration and starts the
-// background writer goroutine.
+// background writer goroutine. The configuration is validated, and defaults
+// are filled in, before anything is created, so a failed Init allocates no
+// resources.
 func Init(cfg *Config) error {
 	if cfg == nil {
 		cfg = &Config{}
 	}
+	defaulted := cfg.Location == ""
+	logLevel, err := resolveConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Optionally swap an unwritable default location for a user-writable one.
+	unwritable := ""
+	if defaulted && cfg.FallbackLocation && cfg.FS == nil && !dirWritable(cfg.Location) {
+		fallback, err := fallbackLocation(cfg.AppName)
+		if err != nil {
+			return fmt.Errorf("default location %s is not writable: %w", cfg.Location, err)
+		}
+		unwritable, cfg.Location = cfg.Location, fallback
+	}
+
+	// Work on the real directory when Location is reached through symlinks;
+	// cfg.Location keeps the path as configured.
+	path := cfg.Location
+	if cfg.FS == nil {
+		if path, err = resolveLocation(cfg.Location); err != nil {
+			return err
+		}
+	}
+
+	l := newLogging(cfg, logLevel)
+	l.path = path
+	mu.Lock()
+	logger.Store(l)
+	mu.Unlock()
+	if cfg.FS == nil {
+		os.Mkdir(path, 0755)
+	}
+	if cfg.SyncForTest {
+		l.openOutputs()
+	} else {
+		go l.start()
+	}
+	for _, log := range endPreInit() {
+		if cfg.ReplayPreInit {
+			l.addLog(log)
+		}
+	}
+	if unwritable != "" {
+		Warnf("log location %s is not writable, logging to %s instead", unwritable, cfg.Location)
+	}
+
+	// Optionally install automatic graceful shutdown on common termination signals.
+	if cfg.AutoStop {
+		installAutoStop(l)
+	}
+
+	// Optionally let console writes to a closed stdout pipe fail instead of
+	// killing the process, so the console can be disabled and file logging
+	// continue.
+	if cfg.IgnoreSIGPIPE {
+		installPipeHandler(l)
+	}
+
+	// Optionally reopen the log file when logrotate signals with SIGUSR1.
+	if cfg.ReopenOnSIGUSR1 {
+		installReopenHandler(l)
+	}
+	// Optionally flush buffered output on SIGUSR2.
+	if cfg.FlushOnSIGUSR2 {
+		installFlushHandler(l)
+	}
+	if cfg.Heartbeat > 0 {
+		go l.heartbeat(cfg.Heartbeat)
+	}
+	return nil
+}
+
+// resolveConfig applies defaults to cfg and validates it, returning the
+// severity of the configured level. It has no side effects beyond cfg.
+func resolveConfig(cfg *Config) (int, error) {
 	if cfg.AppName == "" {
-		return errors.New("AppName is required")
+		return 0, errors.New("AppName is required")
+	}
+	if err := applyPreset(cfg); err != nil {
+		return 0, err
 	}
 	if cfg.Location == "" {
-		if runtime.GOOS == "windows" {
-			cfg.Location = fmt.Sprintf("C:\\ProgramData\\%s\\logs", cfg.AppName)
-		} else {
-			cfg.Location = fmt.Sprintf("/var/log/%s", cfg.AppName)
+		cfg.Location = defaultLocation(cfg.AppName)
+	}
+	// Relative locations are resolved against the working directory now, so
+	// later changes of directory do not move the logs.
+	location, err := filepath.Abs(cfg.Location)
+	if err != nil {
+		return 0, fmt.Errorf("invalid location %s: %w", cfg.Location, err)
+	}
+	cfg.Location = location
+	if len(cfg.Locations) > 0 {
+		locations := make([]string, len(cfg.Locations))
+		for i, dir := range cfg.Locations {
+			abs, err := filepath.Abs(dir)
+			if dir == "" || err != nil {
+				return 0, fmt.Errorf("invalid location in Locations[%d]: %q", i, dir)
+			}
+			locations[i] = abs
+		}
+		cfg.Locations = locations
+	}
+	if strings.ContainsAny(cfg.InstanceID, `/\`) {
+		return 0, fmt.Errorf("invalid instance id: %s", cfg.InstanceID)
+	}
+	if c := cfg.CloudWatch; c != nil {
+		if c.Region == "" || c.LogGroup == "" || c.LogStream == "" {
+			return 0, errors.New("CloudWatch.Region, LogGroup and LogStream are required")
+		}
+		if c.BatchSize > cloudWatchMaxBatch {
+			return 0, fmt.Errorf("CloudWatch.BatchSize must not exceed %d", cloudWatchMaxBatch)
+		}
+	}
+	if err := validateNonNegative(cfg); err != nil {
+		return 0, err
+	}
+	for class, maxAge := range cfg.Retention {
+		if !validRetentionClass(class) {
+			return 0, fmt.Errorf("invalid retention class: %q", class)
+		}
+		if maxAge < 0 {
+			return 0, fmt.Errorf("Retention[%s] must not be negative: %v", class, maxAge)
+		}
+	}
+	for i := range cfg.Sinks {
+		if err := validateSink(fmt.Sprintf("Sinks[%d]", i), &cfg.Sinks[i]); err != nil {
+			return 0, err
+		}
+	}
+	if cfg.FilenameTemplate != "" {
+		if _, err := parseFilenameTemplate(cfg.FilenameTemplate); err != nil {
+			return 0, err
 		}
 	}
 	if cfg.FilePeriod == "" {
 		cfg.FilePeriod = LogPeriodHour
 	}
+	if cfg.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+			return 0, fmt.Errorf("invalid timezone %s: %w", cfg.Timezone, err)
+		}
+	}
+	if !cfg.FilePeriod.valid() {
+		return 0, fmt.Errorf("invalid file period: %s", cfg.FilePeriod)
+	}
+	if ext := cfg.FileExtension; ext != "" {
+		if strings.ContainsAny(ext, `/\`) {
+			return 0, fmt.Errorf("file extension must not contain path separators: %s", ext)
+		}
+		if !strings.HasPrefix(ext, ".") {
+			cfg.FileExtension = "." + ext
+		}
+	}
+	if strings.ContainsAny(cfg.FilePrefix, `/\`) {
+		return 0, fmt.Errorf("file prefix must not contain path separators: %s", cfg.FilePrefix)
+	}
+
+	if len(cfg.LevelSampling) > 0 {
+		rules := make(map[string]SampleRule, len(cfg.LevelSampling))
+		for level, rule := range cfg.LevelSampling {
+			canonical, ok := parseLevel(level)
+			if !ok {
+				return 0, fmt.Errorf("invalid log level in LevelSampling: %s", level)
+			}
+			if rule.BurstAllowance < 0 || rule.Thereafter < 0 {
+				return 0, fmt.Errorf("LevelSampling for %s must not be negative", canonical)
+			}
+			rules[canonical] = rule
+		}
+		cfg.LevelSampling = rules
+	}
+
+	if len(cfg.JSONKeys) > 0 {
+		keys := make(map[string]string, len(cfg.JSONKeys))
+		for name, key := range cfg.JSONKeys {
+			if !jsonKeyNames[name] {
+				return 0, fmt.Errorf("invalid JSONKeys entry %s: must be time, level, msg or caller", name)
+			}
+			if key == "" {
+				return 0, fmt.Errorf("JSONKeys entry %s must not be empty", name)
+			}
+			if cfg.IncludeSeverityNumber && key == "severity" {
+				return 0, fmt.Errorf("JSONKeys entry %s clashes with the severity number key", name)
+			}
+			keys[name] = key
+		}
+		cfg.JSONKeys = keys
+	}
+
+	for _, f := range cfg.FieldFilters {
+		if err := f.validate(); err != nil {
+			return 0, err
+		}
+	}
+
+	for i, sink := range cfg.CustomSinks {
+		if sink == nil {
+			return 0, fmt.Errorf("CustomSinks[%d] is nil", i)
+		}
+	}
+	if cfg.HeartbeatLevel == "" {
+		cfg.HeartbeatLevel = DEBUG
+	}
+	canonical, ok := parseLevel(cfg.HeartbeatLevel)
+	if !ok {
+		return 0, fmt.Errorf("invalid heartbeat level: %s", cfg.HeartbeatLevel)
+	}
+	cfg.HeartbeatLevel = canonical
+
+	if len(cfg.ModuleLevels) > 0 {
+		levels := make(map[string]string, len(cfg.ModuleLevels))
+		for module, level := range cfg.ModuleLevels {
+			canonical, ok := parseLevel(level)
+			if !ok {
+				return 0, fmt.Errorf("invalid log level for module %s: %s", module, level)
+			}
+			levels[module] = canonical
+		}
+		cfg.ModuleLevels = levels
+	}
+	if cfg.CallerStyle == "" {
+		cfg.CallerStyle = CallerStyleString
+	}
+	if cfg.CallerStyle != CallerStyleString && cfg.CallerStyle != CallerStyleFields {
+		return 0, fmt.Errorf("invalid caller style: %s", cfg.CallerStyle)
+	}
+	if cfg.LineEnding == "" {
+		cfg.LineEnding = LineEndingLF
+	}
+	if cfg.LineEnding != LineEndingLF && cfg.LineEnding != LineEndingCRLF {
+		return 0, fmt.Errorf("invalid line ending: %s", cfg.LineEnding)
+	}
+	if cfg.Format == "" {
+		cfg.Format = FormatText
+	}
+	switch cfg.Format {
+	case FormatText, FormatBinary, FormatJSON, FormatCSV:
+	default:
+		return 0, fmt.Errorf("invalid format: %s", cfg.Format)
+	}
+	if !validCompressLevel(cfg.CompressLevel) {
+		return 0, fmt.Errorf("invalid compress level %d: must be between %d and %d", cfg.CompressLevel, gzip.HuffmanOnly, gzip.BestCompression)
+	}
+	if cfg.AuditChain && (cfg.Format == FormatBinary || cfg.Format == FormatCSV) {
+		return 0, errors.New("AuditChain requires a text or JSON format")
+	}
+
+	if cfg.FieldSeparator == "" {
+		cfg.FieldSeparator = defaultFieldSeparator
+	}
+	if strings.ContainsAny(cfg.FieldSeparator, "\r\n") {
+		return 0, fmt.Errorf("invalid field separator: %q", cfg.FieldSeparator)
+	}
+
+	if cfg.ColorScope == "" {
+		cfg.ColorScope = ColorScopeLine
+	}
+	if cfg.ColorScope != ColorScopeLine && cfg.ColorScope != ColorScopeLevel {
+		return 0, fmt.Errorf("invalid color scope: %s", cfg.ColorScope)
+	}
 
 	if cfg.Level == "" {
 		cfg.Level = INFO
 	}
-	logLevel, ok := logLevels[cfg.Level]
+	level, ok := parseLevel(cfg.Level)
 	if !ok {
-		return fmt.Errorf("invalid log level: %s", cfg.Level)
+		return 0, fmt.Errorf("invalid log level: %s", cfg.Level)
 	}
-	logger = newLogging(cfg, logLevel)
-	os.Mkdir(cfg.Location, 0755)
-	go logger.start()
+	cfg.Level = level
+	return logLevels()[level], nil
+}
 
-	// Optionally install automatic graceful shutdown on common termination signals.
-	if cfg.AutoStop {
-		sigc := make(chan os.Signal, 1)
-		signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
-		go func() {
-			<-sigc
-			Stop()
-		}()
+// EffectiveConfig returns a copy of the configuration the running logger is
+// using, with the defaults applied by Init filled in and Level reflecting any
+// later SetLevel. It returns the zero Config when the logger is not running.
+func EffectiveConfig() Config {
+	mu.Lock()
+	defer mu.Unlock()
+	l := logger.Load()
+	if l == nil {
+		return Config{}
+	}
+	cfg := *l.config
+	cfg.FieldOrder = append([]string(nil), cfg.FieldOrder...)
+	cfg.RemoteSinks = append([]RemoteSink(nil), cfg.RemoteSinks...)
+	cfg.Sinks = append([]SinkConfig(nil), cfg.Sinks...)
+	if cfg.DefaultFields != nil {
+		cfg.DefaultFields = make(Fields, len(l.config.DefaultFields))
+		for k, v := range l.config.DefaultFields {
+			cfg.DefaultFields[k] = v
+		}
+	}
+	if level, ok := levelForRank(int(l.logLevel.Load())); ok {
+		cfg.Level = level
+	}
+	return cfg
+}
+
+// validateNonNegative rejects negative sizes, counts, and durations.
+func validateNonNegative(cfg *Config) error {
+	type setting struct {
+		name  string
+		value int64
+	}
+	settings := []setting{
+		{"BufferSize", int64(cfg.BufferSize)},
+		{"MaxBlockDuration", int64(cfg.MaxBlockDuration)},
+		{"IdleTimeout", int64(cfg.IdleTimeout)},
+		{"CompressConcurrency", int64(cfg.CompressConcurrency)},
+		{"BatchWindow", int64(cfg.BatchWindow)},
+		{"BatchSize", int64(cfg.BatchSize)},
+		{"HookWorkers", int64(cfg.HookWorkers)},
+		{"FlushInterval", int64(cfg.FlushInterval)},
+		{"FlushThreshold", int64(cfg.FlushThreshold)},
+		{"MaxFields", int64(cfg.MaxFields)},
+		{"DropWarnStep", int64(cfg.DropWarnStep)},
+		{"DedupWindow", int64(cfg.DedupWindow)},
+		{"SmoothRate", int64(cfg.SmoothRate)},
+		{"PauseBufferSize", int64(cfg.PauseBufferSize)},
+		{"Heartbeat", int64(cfg.Heartbeat)},
+		{"RemoteBuffer.Size", int64(cfg.RemoteBuffer.Size)},
+		{"RemoteBuffer.Backoff", int64(cfg.RemoteBuffer.Backoff)},
+		{"RemoteTimeout", int64(cfg.RemoteTimeout)},
+	}
+	if r := cfg.RemoteBuffer.MaxRetries; r != nil {
+		settings = append(settings, setting{"RemoteBuffer.MaxRetries", int64(*r)})
+	}
+	if c := cfg.CloudWatch; c != nil {
+		settings = append(settings,
+			setting{"CloudWatch.BatchSize", int64(c.BatchSize)},
+			setting{"CloudWatch.FlushInterval", int64(c.FlushInterval)},
+			setting{"CloudWatch.QueueSize", int64(c.QueueSize)},
+		)
+	}
+	if r := cfg.Sampling; r != nil {
+		settings = append(settings,
+			setting{"Sampling.BurstAllowance", int64(r.BurstAllowance)},
+			setting{"Sampling.Thereafter", int64(r.Thereafter)},
+		)
+	}
+	for _, s := range settings {
+		if s.value < 0 {
+			return fmt.Errorf("%s must not be negative", s.name)
+		}
 	}
 	return nil
 }
@@ -112,8 +620,69 @@ func Init(cfg *Config) error {
 // - LogPeriodMonth => nexus_YYYY-MM.log
 // - LogPeriodYear  => nexus_YYYY.log
 //
-// If an unknown period is configured, a daily filename is used as a fallback.
+// When `Config.InstanceID` is set it is appended after the date part, e.g.
+// nexus_YYYY-MM-DD_<instance>.log, so instances sharing a directory each
+// write their own file.
+//
+// The "nexus" prefix is replaced by `Config.FilePrefix` when set. If an
+// unknown period is configured (only possible when Init is bypassed), a
+// daily filename is used as a fallback and a WARN is logged once.
 func (l *Logging) filename(t time.Time) string {
+	return l.filenameFor(t, "")
+}
+
+// filenameFor derives the filename for timestamp t and level stream. An empty
+// level yields the combined filename; otherwise the lowercased level follows
+// the prefix, e.g. nexus_error_YYYY-MM-DD.log (see `Config.SeparateByLevel`).
+// The extension follows the format (see fileExtension).
+// `Config.FilenameTemplate`, when set, replaces this scheme.
+func (l *Logging) filenameFor(t time.Time, level string) string {
+	t = l.inZone(t)
+	if l.template != nil {
+		return l.template.render(l, t, level)
+	}
+	name := l.filePrefix() + "_"
+	if level != "" {
+		name += strings.ToLower(level) + "_"
+	}
+	name += l.datePart(t)
+	if l.config.InstanceID != "" {
+		name += "_" + l.config.InstanceID
+	}
+	return name + l.fileExtension()
+}
+
+// fileExtension returns `Config.FileExtension`, or the extension for
+// `Config.Format`: .jsonl for JSON, .csv for CSV and .log otherwise.
+func (l *Logging) fileExtension() string {
+	if l.config.FileExtension != "" {
+		return l.config.FileExtension
+	}
+	switch l.config.Format {
+	case FormatJSON:
+		return ".jsonl"
+	case FormatCSV:
+		return ".csv"
+	}
+	return ".log"
+}
+
+// defaultFilePrefix starts filenames when `Config.FilePrefix` is not set.
+const defaultFilePrefix = "nexus"
+
+// filePrefix returns `Config.FilePrefix`, or defaultFilePrefix when unset.
+func (l *Logging) filePrefix() string {
+	if l.config.FilePrefix != "" {
+		return l.config.FilePrefix
+	}
+	return defaultFilePrefix
+}
+
+// datePart formats t for the configured rotation period, falling back to a
+// daily date for unknown periods and flagging them for warnUnknownPeriod.
+// With `Config.FilenameTZSuffix` t's UTC offset follows, e.g.
+// 2025-01-02T15+0530.
+func (l *Logging) datePart(t time.Time) string {
 	datePart := ""
 	switch l.config.FilePeriod {
 	case LogPeriodHour:
@@ -128,187 +697,427 @@ func (l *Logging) filename(t time.Time) string {
 	case LogPeriodYear:
 		datePart = t.Format("2006")
 	default:
-		return fmt.Sprintf("nexus_%s.log", t.Format("2006-01-02"))
+		l.unknownPeriod.Store(true)
+		datePart = t.Format("2006-01-02")
 	}
-	return fmt.Sprintf("nexus_%s.log", datePart)
+	if l.config.FilenameTZSuffix {
+		datePart += t.Format("-0700")
+	}
+	return datePart
 }
 
-// start runs the background writer loop. It listens on l.logChan and appends
-// formatted log lines to the appropriate file (as determined by filename()).
-//
-// Notes:
-// - Files are opened in append mode and created if they don't exist.
-// - I/O errors are written to stderr and the loop continues.
-// - The loop terminates when the channel is closed by Stop().
-func (l *Logging) start() {
-	for log := range l.logChan {
-		filename := l.filename(log.TimeStamp)
-		fullpath := filepath.Join(l.path, filename)
-
-		// Open the file in append mode, or create it if it doesn't exist.
-		file, err := os.OpenFile(fullpath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			// If the log file can't be opened, print an error to stderr and continue.
-			fmt.Fprintf(os.Stderr, "ERROR: could not open log file %s: %v\n", fullpath, err)
-			continue
-		}
+// dirFor returns the directory holding the files for timestamp t: Location,
+// or with `Config.Locations` the directory whose turn it is for t's period.
+// Periods take turns in order, so the directory is derived from the time
+// alone and readers can find any file.
+func (l *Logging) dirFor(t time.Time) string {
+	if len(l.config.Locations) == 0 {
+		return l.path
+	}
+	return l.config.Locations[l.periodIndex(l.inZone(t))%len(l.config.Locations)]
+}
 
-		output := fmt.Sprintf("%s\t%s\t%s\n", log.TimeStamp.Format("15:04:05"), log.Level, log.Message)
+// periodIndex numbers the rotation periods so consecutive periods get
+// consecutive numbers.
+func (l *Logging) periodIndex(t time.Time) int {
+	y, m, d := t.Date()
+	days := int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+	switch l.config.FilePeriod {
+	case LogPeriodHour:
+		return days*24 + t.Hour()
+	case LogPeriodWeek:
+		y, w := t.ISOWeek()
+		return y*53 + w
+	case LogPeriodMonth:
+		return y*12 + int(m)
+	case LogPeriodYear:
+		return y
+	default:
+		return days
+	}
+}
 
-		// Write the log message to the file.
-		if _, err := file.WriteString(output); err != nil {
-			fmt.Fprintf(os.Stderr, "ERROR: could not write to log file %s: %v\n", fullpath, err)
-		}
+// previousPeriod returns a time in the rotation period before the one
+// holding t.
+func (l *Logging) previousPeriod(t time.Time) time.Time {
+	switch l.config.FilePeriod {
+	case LogPeriodHour:
+		return t.Add(-time.Hour)
+	case LogPeriodWeek:
+		return t.AddDate(0, 0, -7)
+	case LogPeriodMonth:
+		y, m, _ := t.Date()
+		return time.Date(y, m-1, 1, 0, 0, 0, 0, t.Location())
+	case LogPeriodYear:
+		return time.Date(t.Year()-1, 1, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return t.AddDate(0, 0, -1)
+	}
+}
+
+// PathFor returns the path of the combined log file that holds entries
+// logged at t, taking `Config.Locations` into account. It returns an empty
+// string when the logger is not initialized.
+func PathFor(t time.Time) string {
+	mu.Lock()
+	defer mu.Unlock()
+	l := logger.Load()
+	if l == nil {
+		return ""
+	}
+	return l.filePathFor(t)
+}
 
-		// Close the file handle.
-		file.Close()
+// enabled reports whether l emits entries at level. The level helpers load
+// the package logger once and check it first so filtered calls skip
+// formatting entirely. With no logger (a nil l) every level is enabled
+// while CapturePreInit is keeping entries for `Config.ReplayPreInit`, and
+// none otherwise.
+func (l *Logging) enabled(level string) bool {
+	if l == nil {
+		return capturingPreInit()
 	}
+	return logLevels()[level] >= l.floor()
 }
 
-// addLog applies level filtering, writes to console with color, and enqueues
-// the entry for async file persistence.
+// IsEnabled reports whether entries at level (a level constant such as
+// DEBUG, or a registered level) would be logged, so hot paths can skip
+// building expensive messages:
+//
+//	if chronos.IsEnabled(chronos.DEBUG) {
+//		chronos.Debug(dump(state))
+//	}
+//
+// It reads the level atomically and allocates nothing. With
+// `Config.ModuleLevels` it reports true if any module logs at level.
+func IsEnabled(level string) bool {
+	l := logger.Load()
+	return l != nil && l.enabled(level)
+}
+
+// addLog applies level filtering, adds caller information when enabled,
+// merges the default fields, applies any per-module level, sampling, and
+// error deduplication, and runs the entry through the registered middleware chain before it is
+// emitted (or held back in quiet mode).
 func (l *Logging) addLog(log Log) {
-	if logger == nil {
+	if l == nil {
+		if capturingPreInit() {
+			keepPreInit(log)
+		}
 		return
 	}
-	if logLevels[log.Level] < l.logLevel {
+	severity := logLevels()[log.Level]
+	if severity < l.floor() {
 		return
 	}
+	if (l.config.IncludeModule || l.config.IncludeCaller) && !log.replayed {
+		log.Fields = l.withCaller(log.Fields)
+	}
+	if l.config.IncludeGoroutineID && !log.replayed {
+		log.Fields = withGoroutineID(log.Fields)
+	}
+	log.Fields = l.withDefaults(log.Fields)
+	if len(l.config.FieldFilters) > 0 && !l.keep(log.Fields) {
+		return
+	}
+	if l.moduleLevels != nil && severity < l.levelFor(log.Fields) {
+		return
+	}
+	if l.sampler != nil && !l.sampler.allow(log) {
+		return
+	}
+	if l.dedup != nil {
+		var ok bool
+		if log, ok = l.dedup.allow(log); !ok {
+			return
+		}
+	}
+	if h := pipeline.Load(); h != nil {
+		(*h)(log)
+		return
+	}
+	l.deliver(log)
+}
 
-	// Color codes for terminal output
-	const (
-		colorRed    = "\033[31m"
-		colorGreen  = "\033[32m"
-		colorYellow = "\033[33m"
-		colorBlue   = "\033[34m"
-		colorPurple = "\033[35m"
-		colorReset  = "\033[0m"
-	)
-
-	var color string
-	switch log.Level {
-	case "FATAL":
-		color = colorPurple
-	case "ERROR":
-		color = colorRed
-	case "WARN":
-		color = colorYellow
-	case "INFO":
-		color = colorGreen
-	case "DEBUG":
-		color = colorBlue
-	default:
-		color = colorReset
+// deliver passes an entry that made it through filtering and middleware on
+// to emit, or to quiet when `Config.QuietUntilError` holds entries back.
+func (l *Logging) deliver(log Log) {
+	if l.config.QuietUntilError {
+		l.quiet(log)
+		return
 	}
+	l.emit(log)
+}
 
-	fmt.Printf("%s%s\t%s\t%s%s\n", color, log.TimeStamp.Format("15:04:05"), log.Level, log.Message, colorReset)
-	if externalHandler != nil {
-		externalHandler(log.TimeStamp, log.Level, log.Message)
+// emit resolves Lazy fields, caps them at `Config.MaxFields`, sanitizes
+// invalid UTF-8 if enabled, writes the entry to the console writer with
+// color, invokes the external handler (or queues it for the hook workers),
+// and enqueues the entry for async file persistence.
+func (l *Logging) emit(log Log) {
+	log.Fields = resolveFields(log.Fields)
+	if max := l.config.MaxFields; max > 0 {
+		log.Fields = truncateFields(log.Fields, max, l.config.FieldOrder)
+	}
+	if l.sanitizeUTF8() {
+		log = sanitize(log)
+	}
+	l.writeConsole(log)
+	if l.hooks != nil {
+		l.hooks.dispatch(log)
+	} else {
+		callHandler(log)
+	}
+	if !l.hold(log) {
+		l.enqueue(log)
 	}
-	l.logChan <- log
 }
 
 // Stop gracefully shuts down the logger and releases the package-level logger.
 func Stop() {
 	mu.Lock()
 	defer mu.Unlock()
-	if logger == nil {
+	l := logger.Load()
+	if l == nil {
 		return
 	}
-	close(logger.logChan)
-	logger = nil
+	l.stop()
+	logger.Store(nil)
+}
+
+// stop ends any pause, flushes the console, closes the queue so the writer
+// drains and exits, waits for the hook workers, and removes the signal
+// handlers of l. The caller holds mu and clears logger.
+func (l *Logging) stop() {
+	l.resume()
+	l.console.flush()
+	if l.config.SyncForTest {
+		l.stopInline()
+	}
+	close(l.quit)
+	if l.hooks != nil {
+		l.hooks.stop()
+	}
+	l.sendMu.Lock()
+	l.stopped.Store(true)
+	close(l.logChan)
+	l.sendMu.Unlock()
+}
+
+// installAutoStop stops l when the process receives SIGINT or SIGTERM. The
+// handler belongs to l: it is removed as soon as l stops, however that
+// happens, and a signal arriving after the application has already called
+// Stop, or after a later Init, leaves the current logger alone.
+func installAutoStop(l *Logging) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(sigc)
+		select {
+		case <-sigc:
+			mu.Lock()
+			if logger.Load() == l {
+				l.stop()
+				logger.Store(nil)
+			}
+			mu.Unlock()
+		case <-l.quit:
+		}
+	}()
 }
 
 // Error logs a message at ERROR level.
 func Error(msg string) {
-	if logger == nil {
+	l := logger.Load()
+	if !l.enabled(ERROR) {
 		return
 	}
 	log := Log{
-		TimeStamp: time.Now(),
+		TimeStamp: clock(),
 		Level:     "ERROR",
 		Message:   msg,
 	}
-	logger.addLog(log)
+	l.addLog(log)
 }
 
 // Info logs a message at INFO level.
 func Info(msg string) {
-	if logger == nil {
+	l := logger.Load()
+	if !l.enabled(INFO) {
 		return
 	}
 	log := Log{
-		TimeStamp: time.Now(),
+		TimeStamp: clock(),
 		Level:     "INFO",
 		Message:   msg,
 	}
-	logger.addLog(log)
+	l.addLog(log)
 }
 
 // Debug logs a message at DEBUG level.
 func Debug(msg string) {
-	if logger == nil {
+	l := logger.Load()
+	if !l.enabled(DEBUG) {
 		return
 	}
 	log := Log{
-		TimeStamp: time.Now(),
+		TimeStamp: clock(),
 		Level:     "DEBUG",
 		Message:   msg,
 	}
-	logger.addLog(log)
+	l.addLog(log)
 }
 
 // Warn logs a message at WARN level.
 func Warn(msg string) {
-	if logger == nil {
+	l := logger.Load()
+	if !l.enabled(WARN) {
 		return
 	}
 	log := Log{
-		TimeStamp: time.Now(),
+		TimeStamp: clock(),
 		Level:     "WARN",
 		Message:   msg,
 	}
-	logger.addLog(log)
+	l.addLog(log)
 }
 
 // Fatal logs a message at FATAL level.
 func Fatal(msg string) {
-	if logger == nil {
+	l := logger.Load()
+	if !l.enabled(FATAL) {
 		return
 	}
 	log := Log{
-		TimeStamp: time.Now(),
+		TimeStamp: clock(),
 		Level:     "FATAL",
 		Message:   msg,
 	}
-	logger.addLog(log)
+	l.addLog(log)
+}
+
+// Panic logs a message at PANIC level, waits for it to be written, and then
+// panics with the message so deferred recovers can handle it.
+func Panic(msg string) {
+	if l := logger.Load(); l != nil {
+		log := Log{
+			TimeStamp: clock(),
+			Level:     PANIC,
+			Message:   msg,
+		}
+		l.addLog(log)
+		l.flush()
+	}
+	panic(msg)
+}
+
+// ErrorSync logs a message at ERROR level and blocks until that entry has
+// been written and synced to disk, returning any error the writer hit. It
+// returns nil without waiting if ERROR is filtered, and nil if middleware
+// dropped the entry. While the logger is paused it returns an error at once,
+// as the entry is only written on Resume, if at all.
+func ErrorSync(msg string) error {
+	l := logger.Load()
+	if l == nil || !l.enabled(ERROR) {
+		return nil
+	}
+	done := make(chan error, 1)
+	l.addLog(Log{
+		TimeStamp: clock(),
+		Level:     ERROR,
+		Message:   msg,
+		done:      done,
+	})
+	// Entries are written in order, so once the barrier returns the entry
+	// has been processed, unless it never reached the queue.
+	l.flush()
+	select {
+	case err := <-done:
+		return err
+	default:
+		return nil
+	}
 }
 
 // Errorf logs a formatted message at ERROR level.
 func Errorf(format string, args ...interface{}) {
+	if !logger.Load().enabled(ERROR) {
+		return
+	}
 	Error(fmt.Sprintf(format, args...))
 }
 
 // Infof logs a formatted message at INFO level.
 func Infof(format string, args ...interface{}) {
+	if !logger.Load().enabled(INFO) {
+		return
+	}
 	Info(fmt.Sprintf(format, args...))
 }
 
 // Debugf logs a formatted message at DEBUG level.
 func Debugf(format string, args ...interface{}) {
+	if !logger.Load().enabled(DEBUG) {
+		return
+	}
 	Debug(fmt.Sprintf(format, args...))
 }
 
 // Warnf logs a formatted message at WARN level.
 func Warnf(format string, args ...interface{}) {
+	if !logger.Load().enabled(WARN) {
+		return
+	}
 	Warn(fmt.Sprintf(format, args...))
 }
 
 // Fatalf logs a formatted message at FATAL level.
 func Fatalf(format string, args ...interface{}) {
+	if !logger.Load().enabled(FATAL) {
+		return
+	}
 	Fatal(fmt.Sprintf(format, args...))
 }
 
+// Panicf logs a formatted message at PANIC level and then panics.
+func Panicf(format string, args ...interface{}) {
+	Panic(fmt.Sprintf(format, args...))
+}
+
+// LogAt logs a message at the named level, built-in or registered with
+// RegisterLevel. Names are matched like `Config.Level`; an unknown level is
+// reported through `Config.ErrorHandler` (or stderr) and the entry dropped.
+func LogAt(level, msg string) {
+	l := logger.Load()
+	if l == nil {
+		return
+	}
+	canonical, ok := parseLevel(level)
+	if !ok {
+		l.reportError(fmt.Errorf("invalid log level: %s", level))
+		return
+	}
+	if canonical == PANIC {
+		Panic(msg)
+	}
+	if !l.enabled(canonical) {
+		return
+	}
+	log := Log{
+		TimeStamp: clock(),
+		Level:     canonical,
+		Message:   msg,
+	}
+	l.addLog(log)
+}
+
+// LogAtf logs a formatted message at the named level (see LogAt).
+func LogAtf(level, format string, args ...interface{}) {
+	if canonical, ok := parseLevel(level); ok && canonical != PANIC && !logger.Load().enabled(canonical) {
+		return
+	}
+	LogAt(level, fmt.Sprintf(format, args...))
+}
+
 func SetHandler(handler func(time.Time, string, string)) {
 	externalHandler = handler
 }
@@ -0,0 +1,63 @@
+// middleware.go
+//
+// # Chronos Logging - Middleware
+//
+// Provides a composable middleware pipeline that is evaluated in
+// `Logging.addLog()` for every entry that passes level filtering. Each
+// middleware can inspect or modify a `Log` (add fields, redact the message)
+// or drop it entirely by not calling the next handler.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// HandlerFunc processes a single log entry. The final handler in the chain
+// prints the entry to the console, invokes the external handler, and enqueues
+// it for file persistence.
+type HandlerFunc func(log Log)
+
+// Middleware wraps a HandlerFunc. A middleware that does not call next drops
+// the entry.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+var (
+	middlewareMu sync.Mutex
+	middlewares  []Middleware
+	// pipeline is the middleware chain built by Use, ending in dispatch. It
+	// is nil while no middleware is registered.
+	pipeline atomic.Pointer[HandlerFunc]
+)
+
+// Use registers a middleware. Middlewares run in registration order: the
+// first registered sees each entry first. The chain is built here, once, so
+// each Middleware is called to wrap next when Use is called rather than for
+// every entry.
+func Use(mw Middleware) {
+	if mw == nil {
+		return
+	}
+	middlewareMu.Lock()
+	defer middlewareMu.Unlock()
+	middlewares = append(middlewares, mw)
+	h := HandlerFunc(dispatch)
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	pipeline.Store(&h)
+}
+
+// dispatch ends the middleware chain, handing the entry to the running
+// logger. Entries passed on after Stop are dropped.
+func dispatch(log Log) {
+	if l := logger.Load(); l != nil {
+		l.deliver(log)
+	}
+}
@@ -0,0 +1,145 @@
+// middleware_test.go
+//
+// # Package logging - Middleware Tests
+//
+// Covers composition and ordering of the middleware pipeline.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// resetMiddleware removes all registered middleware.
+func resetMiddleware() {
+	middlewareMu.Lock()
+	middlewares = nil
+	pipeline.Store(nil)
+	middlewareMu.Unlock()
+}
+
+// TestMiddlewareChain verifies that two chained middlewares both take effect:
+// the first adds a field and the second uppercases the message.
+func TestMiddlewareChain(t *testing.T) {
+	Stop()
+	resetMiddleware()
+	defer resetMiddleware()
+
+	tempDir, err := os.MkdirTemp("", "middleware")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	var order []string
+	Use(func(next HandlerFunc) HandlerFunc {
+		return func(log Log) {
+			order = append(order, "field")
+			next(log.WithField("request_id", "abc123"))
+		}
+	})
+	Use(func(next HandlerFunc) HandlerFunc {
+		return func(log Log) {
+			order = append(order, "upper")
+			log.Message = strings.ToUpper(log.Message)
+			next(log)
+		}
+	})
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	l := newLogging(cfg, logLevels()[INFO])
+	logger.Store(l)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		l.start()
+	}()
+
+	Info("middleware message")
+	filename := l.filename(time.Now())
+	Stop()
+	wg.Wait()
+
+	content, err := os.ReadFile(filepath.Join(tempDir, filename))
+	if err != nil {
+		t.Fatalf("could not read log file: %v", err)
+	}
+	line := string(content)
+	if !strings.Contains(line, "MIDDLEWARE MESSAGE") {
+		t.Errorf("expected uppercased message, got %q", line)
+	}
+	if !strings.Contains(line, "request_id=abc123") {
+		t.Errorf("expected request_id field, got %q", line)
+	}
+	if len(order) != 2 || order[0] != "field" || order[1] != "upper" {
+		t.Errorf("expected middleware to run in registration order, got %v", order)
+	}
+}
+
+// TestMiddlewareDrop ensures a middleware that does not call next drops the
+// entry before it is enqueued.
+func TestMiddlewareDrop(t *testing.T) {
+	Stop()
+	resetMiddleware()
+	defer resetMiddleware()
+
+	Use(func(next HandlerFunc) HandlerFunc {
+		return func(log Log) {
+			if strings.Contains(log.Message, "secret") {
+				return
+			}
+			next(log)
+		}
+	})
+
+	logger.Store(newLogging(getConfig(), logLevels()[INFO]))
+	defer Stop()
+
+	Info("contains secret")
+	Info("public")
+	if len(logger.Load().logChan) != 1 {
+		t.Errorf("expected 1 message in log channel, got %d", len(logger.Load().logChan))
+	}
+}
+
+// TestMiddlewareBuiltOnce asserts the chain is built by Use and not rebuilt
+// for each entry.
+func TestMiddlewareBuiltOnce(t *testing.T) {
+	Stop()
+	resetMiddleware()
+	defer resetMiddleware()
+
+	var builds, calls int
+	Use(func(next HandlerFunc) HandlerFunc {
+		builds++
+		return func(log Log) {
+			calls++
+			next(log)
+		}
+	})
+
+	logger.Store(newLogging(getConfig(), logLevels()[INFO]))
+	defer Stop()
+
+	for i := 0; i < 3; i++ {
+		Info("entry")
+	}
+	if builds != 1 || calls != 3 {
+		t.Errorf("expected 1 build and 3 calls, got %d and %d", builds, calls)
+	}
+	if len(logger.Load().logChan) != 3 {
+		t.Errorf("expected 3 messages in log channel, got %d", len(logger.Load().logChan))
+	}
+}
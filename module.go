@@ -0,0 +1,85 @@
+// module.go
+//
+// # Chronos Logging - Source Module Field
+//
+// With `Config.IncludeModule`, each entry is tagged with the import path of
+// the Go package that logged it, taken from the caller's frame (see
+// caller.go). `Config.ModuleLevels` gives components and modules their own
+// minimum level.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"math"
+	"runtime"
+	"strings"
+)
+
+// moduleField is the field key holding the caller's package path.
+const moduleField = "module"
+
+// componentField is the field key naming an entry's component for
+// `Config.ModuleLevels`.
+const componentField = "component"
+
+// chronosPackage is the import path of this package, used to skip its own
+// frames when looking for the caller.
+var chronosPackage = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	return packageOf(runtime.FuncForPC(pc).Name())
+}()
+
+// packageOf returns the package path of a fully qualified function name such
+// as "github.com/acme/app/db.(*Conn).Query".
+func packageOf(function string) string {
+	slash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[slash+1:], "."); dot >= 0 {
+		return function[:slash+1+dot]
+	}
+	return function
+}
+
+// newModuleLevels converts `Config.ModuleLevels` to severities, skipping
+// invalid levels (Init rejects them), and returns the lowest of them, or
+// math.MaxInt when there are none.
+func newModuleLevels(levels map[string]string) (map[string]int, int) {
+	floor := math.MaxInt
+	if len(levels) == 0 {
+		return nil, floor
+	}
+	severities := make(map[string]int, len(levels))
+	for module, level := range levels {
+		canonical, ok := parseLevel(level)
+		if !ok {
+			continue
+		}
+		severities[module] = logLevels()[canonical]
+		floor = min(floor, logLevels()[canonical])
+	}
+	return severities, floor
+}
+
+// floor returns the lowest severity any entry can be logged at: the global
+// level, or a lower module level.
+func (l *Logging) floor() int {
+	return min(int(l.logLevel.Load()), l.moduleFloor)
+}
+
+// levelFor returns the minimum severity for an entry with the given fields:
+// the level in `Config.ModuleLevels` for its component field or, failing
+// that, its module field, and otherwise the global level.
+func (l *Logging) levelFor(fields Fields) int {
+	for _, key := range []string{componentField, moduleField} {
+		if name, ok := fields[key].(string); ok {
+			if severity, ok := l.moduleLevels[name]; ok {
+				return severity
+			}
+		}
+	}
+	return int(l.logLevel.Load())
+}
@@ -0,0 +1,111 @@
+// module_test.go
+//
+// # Chronos Logging - Source Module Field Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// logFromHelper logs through a couple of package helpers so the module
+// lookup has to walk past them.
+func logFromHelper() {
+	WithFields(Fields{"step": 1}).Infof("from %s", "helper")
+}
+
+// TestIncludeModule asserts the module field holds this package's import
+// path for an entry logged from a test helper.
+func TestIncludeModule(t *testing.T) {
+	Stop()
+	var got Log
+	Use(func(next HandlerFunc) HandlerFunc {
+		return func(log Log) {
+			got = log
+			next(log)
+		}
+	})
+	defer resetMiddleware()
+
+	cfg := getConfig()
+	cfg.IncludeModule = true
+	l := newLogging(cfg, logLevels()[INFO])
+	l.opener = newMemFS().open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+	defer func() {
+		Stop()
+		<-done
+	}()
+
+	logFromHelper()
+	const want = "github.com/markoxley/chronos"
+	if got.Fields[moduleField] != want {
+		t.Errorf("expected module %q, got %v", want, got.Fields[moduleField])
+	}
+	if got.Fields["step"] != 1 {
+		t.Errorf("expected caller fields to be kept, got %v", got.Fields)
+	}
+}
+
+// TestPackageOf checks package extraction from qualified function names.
+func TestPackageOf(t *testing.T) {
+	for name, want := range map[string]string{
+		"github.com/acme/app/db.(*Conn).Query": "github.com/acme/app/db",
+		"main.main":                            "main",
+		"net/http.HandlerFunc.ServeHTTP":       "net/http",
+		"github.com/acme/app.run.func1":        "github.com/acme/app",
+	} {
+		if got := packageOf(name); got != want {
+			t.Errorf("packageOf(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TestModuleLevels runs with a global INFO level and db=DEBUG, asserting a
+// DEBUG entry for db is kept while a DEBUG entry for http is dropped.
+func TestModuleLevels(t *testing.T) {
+	Stop()
+	fs := newMemFS()
+	cfg := getConfig()
+	cfg.ModuleLevels = map[string]string{"db": "debug"}
+	level, err := resolveConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := newLogging(cfg, level)
+	l.opener = fs.open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	now := time.Now()
+	WithFields(Fields{"component": "db"}).Debug("query plan")
+	WithFields(Fields{"component": "http"}).Debug("request headers")
+	Debug("no component")
+	WithFields(Fields{"component": "http"}).Info("request served")
+	Stop()
+	<-done
+
+	content := fs.file(l.filePathFor(now)).String()
+	if !strings.Contains(content, "\tquery plan\t") || !strings.Contains(content, "\trequest served\t") {
+		t.Errorf("expected db DEBUG and http INFO entries, got %q", content)
+	}
+	if strings.Contains(content, "request headers") || strings.Contains(content, "no component") {
+		t.Errorf("expected DEBUG entries outside db to be dropped, got %q", content)
+	}
+}
@@ -0,0 +1,147 @@
+// overflow.go
+//
+// # Chronos Logging - Queue Overflow
+//
+// Bounds how long producers can be stalled by a full queue. By default a
+// full queue blocks the caller until the writer catches up. With
+// `Config.MaxBlockDuration` set, a caller blocked for longer than that
+// switches the logger into dropping mode: entries are discarded and counted
+// (see Dropped) instead of queued, until the writer drains the queue and
+// blocking resumes. A single WARN then records how many entries were lost.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultBufferSize is the queue capacity used when `Config.BufferSize` is
+// not set.
+const defaultBufferSize = 10000
+
+// enqueue hands log to the writer, blocking while the queue is full unless
+// `Config.MaxBlockDuration` has been exceeded. Entries logged once the
+// logger has stopped are discarded.
+func (l *Logging) enqueue(log Log) {
+	if l.config.SyncForTest {
+		l.inline(log)
+		return
+	}
+	l.sendMu.RLock()
+	defer l.sendMu.RUnlock()
+	if l.stopped.Load() {
+		return
+	}
+	if l.config.MaxBlockDuration <= 0 {
+		l.logChan <- log
+		return
+	}
+	select {
+	case l.logChan <- log:
+		return
+	default:
+	}
+	if l.dropping.Load() {
+		l.dropped.Add(1)
+		return
+	}
+
+	timer := time.NewTimer(l.config.MaxBlockDuration)
+	defer timer.Stop()
+	select {
+	case l.logChan <- log:
+	case <-timer.C:
+		l.dropped.Add(1)
+		l.dropping.Store(true)
+	}
+}
+
+// resumeBlocking is called by the writer when the queue has drained. If the
+// logger was dropping entries it returns to blocking and writes a WARN with
+// the number of entries lost since the last report.
+func (l *Logging) resumeBlocking() {
+	if !l.dropping.Load() {
+		return
+	}
+	l.dropping.Store(false)
+	total := l.dropped.Load()
+	lost := total - l.droppedReported
+	l.droppedReported = total
+	warn := Log{
+		TimeStamp: clock(),
+		Level:     WARN,
+		Message:   fmt.Sprintf("dropped %d log entries: queue was blocked for more than %s", lost, l.config.MaxBlockDuration),
+	}
+	l.writeConsole(warn)
+	l.write(warn)
+}
+
+// Dropped returns the number of entries the running logger has discarded
+// because the queue stayed full for longer than `Config.MaxBlockDuration`.
+func Dropped() uint64 {
+	mu.Lock()
+	defer mu.Unlock()
+	l := logger.Load()
+	if l == nil {
+		return 0
+	}
+	return l.dropped.Load()
+}
+
+// totalDropped returns the number of entries lost to overflow and sampling.
+func (l *Logging) totalDropped() uint64 {
+	n := l.dropped.Load()
+	if l.sampler != nil {
+		n += l.sampler.dropped.Load()
+	}
+	return n
+}
+
+// dropWarnings reports whether drop threshold warnings are enabled.
+func (l *Logging) dropWarnings() bool {
+	return l.config.DropWarnings || l.config.DropWarnStep > 0
+}
+
+// nextDropThreshold returns the smallest threshold above n: the next
+// multiple of `Config.DropWarnStep`, or else the next power of ten.
+func (l *Logging) nextDropThreshold(n uint64) uint64 {
+	if step := uint64(l.config.DropWarnStep); step > 0 {
+		return (n/step + 1) * step
+	}
+	t := uint64(10)
+	for t <= n {
+		t *= 10
+	}
+	return t
+}
+
+// warnDrops is called by the writer after each entry. Once the total drop
+// count reaches the current threshold it writes a single WARN with the
+// total, however many thresholds were crossed, and moves the threshold on.
+func (l *Logging) warnDrops() {
+	if !l.dropWarnings() {
+		return
+	}
+	if l.dropThreshold == 0 {
+		l.dropThreshold = l.nextDropThreshold(0)
+	}
+	total := l.totalDropped()
+	if total < l.dropThreshold {
+		return
+	}
+	crossed := l.dropThreshold
+	l.dropThreshold = l.nextDropThreshold(total)
+	warn := Log{
+		TimeStamp: clock(),
+		Level:     WARN,
+		Message:   fmt.Sprintf("%d log entries have been dropped (threshold %d)", total, crossed),
+	}
+	l.writeConsole(warn)
+	l.write(warn)
+}
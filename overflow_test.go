@@ -0,0 +1,116 @@
+// overflow_test.go
+//
+// # Chronos Logging - Queue Overflow Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMaxBlockDuration stalls the writer behind a slow sink and asserts
+// callers are blocked no longer than MaxBlockDuration, drops are counted, and
+// a single WARN reports them once the queue drains.
+func TestMaxBlockDuration(t *testing.T) {
+	Stop()
+	gw := &gatedWriter{gate: make(chan struct{})}
+	cfg := getConfig()
+	cfg.BufferSize = 1
+	cfg.MaxBlockDuration = 50 * time.Millisecond
+	l := newLogging(cfg, logLevels()[INFO])
+	l.opener = func(string, int, os.FileMode) (io.WriteCloser, error) { return gw, nil }
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	Info("stalls the writer")
+	if !waitFor(t, time.Second, func() bool { return len(l.logChan) == 0 }) {
+		t.Fatal("writer did not pick up the first entry")
+	}
+	start := time.Now()
+	Info("fills the queue")
+	Info("blocks then drops")
+	Info("dropped at once")
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("callers blocked for %v, expected about %v", elapsed, cfg.MaxBlockDuration)
+	}
+	if got := Dropped(); got != 2 {
+		t.Errorf("expected 2 dropped entries, got %d", got)
+	}
+
+	close(gw.gate)
+	l.flush()
+	Stop()
+	<-done
+
+	content := gw.String()
+	if strings.Count(content, "dropped 2 log entries") != 1 {
+		t.Errorf("expected a single WARN reporting the drops, got %q", content)
+	}
+	if strings.Contains(content, "blocks then drops") || !strings.Contains(content, "fills the queue") {
+		t.Errorf("unexpected file content %q", content)
+	}
+}
+
+// TestDropWarnings forces drops through sampling and asserts one WARN per
+// threshold crossed, both with a fixed step and with powers of ten.
+func TestDropWarnings(t *testing.T) {
+	tests := []struct {
+		name string
+		step int
+		want int
+		last string
+	}{
+		{"step", 100, 9, "900 log entries have been dropped (threshold 900)"},
+		{"powers of ten", 0, 2, "108 log entries have been dropped (threshold 100)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Stop()
+			tempDir, err := os.MkdirTemp("", "dropwarn")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(tempDir)
+
+			cfg := getConfig()
+			cfg.Location = tempDir
+			cfg.SyncForTest = true
+			cfg.Sampling = &SampleRule{Thereafter: 10}
+			cfg.DropWarnings = true
+			cfg.DropWarnStep = tt.step
+			if err := Init(cfg); err != nil {
+				t.Fatalf("Init failed: %v", err)
+			}
+			defer Stop()
+
+			// Every 10th entry is kept, so the 101st kept entry follows
+			// 900 drops.
+			for i := 0; i < 1001; i++ {
+				Info("noisy")
+			}
+			content, err := os.ReadFile(PathFor(clock()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Count(string(content), "log entries have been dropped"); got != tt.want {
+				t.Errorf("expected %d drop warnings, got %d", tt.want, got)
+			}
+			if !strings.Contains(string(content), tt.last) {
+				t.Errorf("expected warning %q, got %q", tt.last, content)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// pause.go
+//
+// # Chronos Logging - Pause and Resume
+//
+// Pause stops file output for every level, for example during a noisy bulk
+// import, while the console and the handler keep receiving entries. Paused
+// entries are held in memory, up to `Config.PauseBufferSize`, and queued in
+// their original order by Resume; with `Config.PauseDrop` they are
+// discarded instead. Entries that do not fit are dropped, and Resume logs a
+// single WARN with how many were lost. Stop resumes a paused logger first.
+// ErrorSync returns errPaused rather than waiting for an entry that Pause
+// holds or drops.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errPaused is returned to callers waiting on an entry (see ErrorSync) that
+// was held or dropped because the logger is paused.
+var errPaused = errors.New("logger paused: entry not written until Resume")
+
+// defaultPauseBufferSize bounds the entries held while paused when
+// `Config.PauseBufferSize` is not set.
+const defaultPauseBufferSize = 10000
+
+// Pause stops the running logger writing to its files until Resume. Entries
+// logged meanwhile still reach the console and the handler.
+func Pause() {
+	mu.Lock()
+	defer mu.Unlock()
+	l := logger.Load()
+	if l == nil {
+		return
+	}
+	l.pauseMu.Lock()
+	l.paused.Store(true)
+	l.pauseMu.Unlock()
+}
+
+// Resume restarts file output after Pause, first queueing the entries held
+// while paused in the order they were logged.
+func Resume() {
+	mu.Lock()
+	l := logger.Load()
+	mu.Unlock()
+	if l == nil {
+		return
+	}
+	l.resume()
+}
+
+// resume queues the held entries and ends the pause. The entries are queued
+// without holding pauseMu, so logging carries on while the queue is full;
+// entries logged meanwhile are still held and queued after them.
+func (l *Logging) resume() {
+	l.resumeMu.Lock()
+	defer l.resumeMu.Unlock()
+	l.pauseMu.Lock()
+	if !l.paused.Load() {
+		l.pauseMu.Unlock()
+		return
+	}
+	l.resuming = true
+	for len(l.pauseBuf) > 0 {
+		held := l.pauseBuf
+		l.pauseBuf = nil
+		l.pauseMu.Unlock()
+		for _, log := range held {
+			l.enqueue(log)
+		}
+		l.pauseMu.Lock()
+	}
+	l.paused.Store(false)
+	l.resuming = false
+	lost := l.pauseLost
+	l.pauseLost = 0
+	l.pauseMu.Unlock()
+
+	if lost > 0 && !l.config.PauseDrop {
+		l.emit(Log{
+			TimeStamp: clock(),
+			Level:     WARN,
+			Message:   fmt.Sprintf("dropped %d log entries: pause buffer was full", lost),
+		})
+	}
+}
+
+// hold keeps log for Resume while the logger is paused, reporting whether
+// it took the entry. A caller waiting on the entry gets errPaused at once.
+func (l *Logging) hold(log Log) bool {
+	if !l.paused.Load() {
+		return false
+	}
+	l.pauseMu.Lock()
+	defer l.pauseMu.Unlock()
+	if !l.paused.Load() {
+		return false
+	}
+	if log.done != nil {
+		log.done <- errPaused
+		log.done = nil
+	}
+	size := l.config.PauseBufferSize
+	if size == 0 {
+		size = defaultPauseBufferSize
+	}
+	if (l.config.PauseDrop && !l.resuming) || len(l.pauseBuf) >= size {
+		l.pauseLost++
+		return true
+	}
+	l.pauseBuf = append(l.pauseBuf, log)
+	return true
+}
@@ -0,0 +1,162 @@
+// pause_test.go
+//
+// # Chronos Logging - Pause Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPauseResume pauses file output, logs, and asserts nothing reaches the
+// file until Resume writes the held entries in order, before later ones.
+func TestPauseResume(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+	path := PathFor(time.Now())
+	read := func() string {
+		data, _ := os.ReadFile(path)
+		return string(data)
+	}
+
+	Info("before")
+	Pause()
+	Info("held 1")
+	Warn("held 2")
+	Error("held 3")
+	if got := read(); strings.Contains(got, "held") {
+		t.Fatalf("expected no file output while paused, got %q", got)
+	}
+	Resume()
+	Info("after")
+
+	var order []string
+	for _, line := range strings.Split(strings.TrimSpace(read()), "\n") {
+		cols := strings.Split(line, "\t")
+		order = append(order, cols[len(cols)-1])
+	}
+	want := []string{"before", "held 1", "held 2", "held 3", "after"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, order)
+	}
+}
+
+// TestPauseBounded asserts entries beyond PauseBufferSize are dropped and
+// reported on Resume, and that PauseDrop discards everything silently.
+func TestPauseBounded(t *testing.T) {
+	for _, drop := range []bool{false, true} {
+		Stop()
+		cfg := getConfig()
+		cfg.Location = t.TempDir()
+		cfg.SyncForTest = true
+		cfg.PauseBufferSize = 2
+		cfg.PauseDrop = drop
+		if err := Init(cfg); err != nil {
+			t.Fatalf("Init failed: %v", err)
+		}
+		path := PathFor(time.Now())
+		Pause()
+		for i := 0; i < 5; i++ {
+			Info("paused")
+		}
+		Resume()
+		Stop()
+
+		data, _ := os.ReadFile(path)
+		got := string(data)
+		if drop {
+			if got != "" {
+				t.Errorf("PauseDrop: expected no output, got %q", got)
+			}
+			continue
+		}
+		if n := strings.Count(got, "paused\n"); n != 2 || !strings.Contains(got, "dropped 3 log entries") {
+			t.Errorf("expected 2 held entries and a WARN for 3, got %q", got)
+		}
+	}
+}
+
+// TestPauseErrorSync asserts ErrorSync reports a held entry at once instead
+// of returning nil, and that the entry is written on Resume.
+func TestPauseErrorSync(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+	path := PathFor(time.Now())
+
+	Pause()
+	if err := ErrorSync("held"); err == nil {
+		t.Error("expected ErrorSync to fail while paused")
+	}
+	Resume()
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "\tERROR\theld\n") {
+		t.Errorf("expected the entry to be written on Resume, got %q", data)
+	}
+}
+
+// TestResumeFullQueue resumes onto a full queue and asserts logging is not
+// blocked meanwhile, with later entries still written after the held ones.
+func TestResumeFullQueue(t *testing.T) {
+	Stop()
+	fs := newMemFS()
+	l := newLogging(getConfig(), logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	for len(l.logChan) < cap(l.logChan) {
+		l.logChan <- Log{TimeStamp: time.Now(), Level: INFO, Message: "queued"}
+	}
+
+	now := time.Now()
+	Pause()
+	Info("held")
+	resumed := make(chan struct{})
+	go func() {
+		Resume()
+		close(resumed)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	logged := make(chan struct{})
+	go func() {
+		Info("during")
+		close(logged)
+	}()
+	select {
+	case <-logged:
+	case <-time.After(time.Second):
+		t.Fatal("logging blocked while Resume waited on the queue")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+	<-resumed
+	Stop()
+	<-done
+
+	got := fs.file(l.filePathFor(now)).String()
+	held, during := strings.Index(got, "\theld\n"), strings.Index(got, "\tduring\n")
+	if held < 0 || during < held {
+		t.Errorf("expected the held entry before the later one, got held at %d, during at %d", held, during)
+	}
+}
@@ -0,0 +1,44 @@
+// pool.go
+//
+// # Chronos Logging - Buffer Pool
+//
+// Output lines are built by appending into byte buffers taken from a
+// sync.Pool instead of by string concatenation, so a console line costs no
+// allocation and a file line a single one (the string handed to the file,
+// tee and sinks). A buffer is only ever used by the goroutine that took it
+// and is returned once its bytes have been copied out.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import "sync"
+
+// maxPooledBuffer is the largest buffer returned to the pool; a buffer grown
+// by an unusually large entry is left to the garbage collector instead.
+const maxPooledBuffer = 64 << 10
+
+var bufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *[]byte {
+	b := bufPool.Get().(*[]byte)
+	*b = (*b)[:0]
+	return b
+}
+
+// putBuffer returns b to the pool. b must not be used afterwards.
+func putBuffer(b *[]byte) {
+	if cap(*b) > maxPooledBuffer {
+		return
+	}
+	bufPool.Put(b)
+}
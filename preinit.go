@@ -0,0 +1,85 @@
+// preinit.go
+//
+// # Chronos Logging - Pre-Init Replay
+//
+// Library code often logs before the host application calls Init. After
+// CapturePreInit, and until the first Init, those entries are kept in a
+// small ring buffer holding the most recent preInitCapacity of them.
+// Otherwise they are discarded after a single check, without being
+// formatted. Init empties the buffer and, with `Config.ReplayPreInit`, logs
+// its entries through the new logger, where they are filtered by the
+// configured level like any other entry. Replayed entries keep their
+// original timestamps but carry no caller information.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// preInitCapacity is the number of entries kept before Init.
+const preInitCapacity = 256
+
+// preInit is the ring buffer of entries logged before the first Init. next
+// is the slot the next entry goes in once the buffer is full. capturing is
+// set by CapturePreInit and cleared by Init.
+var preInit struct {
+	mu        sync.Mutex
+	entries   []Log
+	next      int
+	capturing atomic.Bool
+	inited    atomic.Bool
+}
+
+// CapturePreInit keeps the entries logged from now until the first Init, so
+// that an Init with `Config.ReplayPreInit` can replay them. Call it first
+// thing in main, before any library logs. It has no effect once Init has
+// run: entries logged after Stop are discarded.
+func CapturePreInit() {
+	if !preInit.inited.Load() {
+		preInit.capturing.Store(true)
+	}
+}
+
+// capturingPreInit reports whether entries logged while no logger runs are
+// kept for replay.
+func capturingPreInit() bool {
+	return preInit.capturing.Load()
+}
+
+// endPreInit stops capturing for good and returns the captured entries,
+// oldest first. Init calls it.
+func endPreInit() []Log {
+	preInit.inited.Store(true)
+	preInit.capturing.Store(false)
+	return takePreInit()
+}
+
+// keepPreInit keeps log for replay by the next Init, overwriting the
+// oldest entry once the buffer is full.
+func keepPreInit(log Log) {
+	log.replayed = true
+	preInit.mu.Lock()
+	defer preInit.mu.Unlock()
+	if len(preInit.entries) < preInitCapacity {
+		preInit.entries = append(preInit.entries, log)
+		return
+	}
+	preInit.entries[preInit.next] = log
+	preInit.next = (preInit.next + 1) % preInitCapacity
+}
+
+// takePreInit empties the buffer, returning its entries oldest first.
+func takePreInit() []Log {
+	preInit.mu.Lock()
+	defer preInit.mu.Unlock()
+	entries := append(preInit.entries[preInit.next:], preInit.entries[:preInit.next]...)
+	preInit.entries, preInit.next = nil, 0
+	return entries
+}
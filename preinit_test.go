@@ -0,0 +1,109 @@
+// preinit_test.go
+//
+// # Chronos Logging - Pre-Init Capture Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// restartPreInit returns the package to its state before the first Init,
+// with capture enabled when capture is true.
+func restartPreInit(capture bool) {
+	Stop()
+	takePreInit()
+	preInit.inited.Store(false)
+	preInit.capturing.Store(false)
+	if capture {
+		CapturePreInit()
+	}
+}
+
+// TestReplayPreInit logs before Init and asserts the entries that pass the
+// configured level are written once Init runs, and that entries logged
+// after Stop are not replayed by a later Init.
+func TestReplayPreInit(t *testing.T) {
+	restartPreInit(true)
+	defer restartPreInit(false)
+
+	Debug("early detail")
+	Info("early bird")
+	WithFields(Fields{"lib": "db"}).Warn("early warning")
+
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.SyncForTest = true
+	cfg.ReplayPreInit = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	Info("after init")
+	path := PathFor(time.Now())
+	Stop()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	early := strings.Index(got, "early bird")
+	if early < 0 || !strings.Contains(got, "early warning\tlib=db") {
+		t.Fatalf("expected early entries in the file, got %q", got)
+	}
+	if strings.Contains(got, "early detail") {
+		t.Errorf("expected the DEBUG entry to be filtered, got %q", got)
+	}
+	if strings.Index(got, "after init") < early {
+		t.Errorf("expected early entries before later ones, got %q", got)
+	}
+
+	CapturePreInit()
+	Info("not replayed")
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	Stop()
+	if data, _ := os.ReadFile(path); strings.Contains(string(data), "not replayed") {
+		t.Errorf("expected entries logged after Stop to be discarded, got %q", data)
+	}
+}
+
+// TestPreInitDiscarded asserts entries logged before Init are neither kept
+// nor reported as enabled without CapturePreInit.
+func TestPreInitDiscarded(t *testing.T) {
+	restartPreInit(false)
+	if logger.Load().enabled(ERROR) {
+		t.Error("expected no level enabled before Init without capture")
+	}
+	Error("dropped")
+	if entries := takePreInit(); len(entries) != 0 {
+		t.Errorf("expected no entries kept, got %d", len(entries))
+	}
+}
+
+// TestPreInitRing asserts only the most recent entries are kept, oldest
+// first.
+func TestPreInitRing(t *testing.T) {
+	restartPreInit(true)
+	defer restartPreInit(false)
+	for i := 0; i < preInitCapacity+10; i++ {
+		Info(fmt.Sprintf("entry %d", i))
+	}
+	entries := takePreInit()
+	if len(entries) != preInitCapacity {
+		t.Fatalf("expected %d entries, got %d", preInitCapacity, len(entries))
+	}
+	if entries[0].Message != "entry 10" || entries[len(entries)-1].Message != fmt.Sprintf("entry %d", preInitCapacity+9) {
+		t.Errorf("unexpected order: first %q, last %q", entries[0].Message, entries[len(entries)-1].Message)
+	}
+}
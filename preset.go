@@ -0,0 +1,91 @@
+// preset.go
+//
+// # Chronos Logging - Presets
+//
+// Presets apply the settings a log ingestion platform expects in one step,
+// so users need not configure each key and level name themselves.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import "fmt"
+
+// Preset names a bundle of settings for an ingestion platform.
+type Preset string
+
+// Supported presets.
+//
+// - PresetGCP        => JSON for Google Cloud Logging: "timestamp", "severity"
+// and "message" keys, with levels named as Cloud Logging severities
+// - PresetCloudWatch => JSON for CloudWatch Logs: "timestamp" and "message"
+// keys, see also `Config.CloudWatch`
+const (
+	PresetGCP        Preset = "gcp"
+	PresetCloudWatch Preset = "cloudwatch"
+)
+
+// presetKeys holds the JSONKeys each preset applies.
+var presetKeys = map[Preset]map[string]string{
+	PresetGCP:        {"time": "timestamp", "level": "severity", "msg": "message"},
+	PresetCloudWatch: {"time": "timestamp", "msg": "message"},
+}
+
+// gcpSeverities maps levels to Google Cloud Logging severity names. Levels
+// added with RegisterLevel have no Cloud Logging equivalent and are written
+// as gcpDefaultSeverity.
+var gcpSeverities = map[string]string{
+	DEBUG: "DEBUG",
+	INFO:  "INFO",
+	WARN:  "WARNING",
+	ERROR: "ERROR",
+	FATAL: "CRITICAL",
+	PANIC: "ALERT",
+}
+
+// gcpDefaultSeverity is the Cloud Logging severity for entries with no
+// assigned severity.
+const gcpDefaultSeverity = "DEFAULT"
+
+// applyPreset fills in the settings of `Config.Preset`. Settings the user
+// has made explicitly are kept: a preset only sets JSONKeys entries that
+// are not already present.
+func applyPreset(cfg *Config) error {
+	if cfg.Preset == "" {
+		return nil
+	}
+	preset, ok := presetKeys[cfg.Preset]
+	if !ok {
+		return fmt.Errorf("invalid preset: %s", cfg.Preset)
+	}
+	if cfg.Format == "" {
+		cfg.Format = FormatJSON
+	}
+	if cfg.Format != FormatJSON {
+		return fmt.Errorf("preset %s requires the JSON format", cfg.Preset)
+	}
+	keys := make(map[string]string, len(preset)+len(cfg.JSONKeys))
+	for name, key := range preset {
+		keys[name] = key
+	}
+	for name, key := range cfg.JSONKeys {
+		keys[name] = key
+	}
+	cfg.JSONKeys = keys
+	return nil
+}
+
+// jsonLevel returns the level name written in JSON objects: the Cloud
+// Logging severity with PresetGCP, otherwise the level itself.
+func (l *Logging) jsonLevel(level string) string {
+	if l.config.Preset == PresetGCP {
+		if severity, ok := gcpSeverities[level]; ok {
+			return severity
+		}
+		return gcpDefaultSeverity
+	}
+	return level
+}
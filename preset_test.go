@@ -0,0 +1,62 @@
+// preset_test.go
+//
+// # Chronos Logging - Preset Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestPresetGCP asserts the GCP preset writes Cloud Logging keys and
+// severity names, and that JSONKeys entries override it.
+func TestPresetGCP(t *testing.T) {
+	cfg := getConfig()
+	cfg.Preset = PresetGCP
+	if _, err := resolveConfig(cfg); err != nil {
+		t.Fatalf("resolveConfig failed: %v", err)
+	}
+	if cfg.Format != FormatJSON {
+		t.Errorf("expected the preset to select JSON, got %s", cfg.Format)
+	}
+	l := newLogging(cfg, logLevels()[DEBUG])
+
+	want := map[string]string{DEBUG: "DEBUG", INFO: "INFO", WARN: "WARNING", ERROR: "ERROR", FATAL: "CRITICAL", PANIC: "ALERT", "AUDIT": "DEFAULT"}
+	for level, severity := range want {
+		line := l.formatFile(Log{TimeStamp: time.Now(), Level: level, Message: "m"})
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		if got["severity"] != severity || got["message"] != "m" || got["timestamp"] == nil {
+			t.Errorf("%s: expected severity %s with GCP keys, got %q", level, severity, line)
+		}
+	}
+
+	cfg = getConfig()
+	cfg.Preset = PresetGCP
+	cfg.JSONKeys = map[string]string{"msg": "text"}
+	if _, err := resolveConfig(cfg); err != nil {
+		t.Fatalf("resolveConfig failed: %v", err)
+	}
+	if cfg.JSONKeys["msg"] != "text" || cfg.JSONKeys["level"] != "severity" {
+		t.Errorf("expected JSONKeys to override the preset, got %v", cfg.JSONKeys)
+	}
+
+	for _, bad := range []*Config{
+		{AppName: "test", Preset: "aws"},
+		{AppName: "test", Preset: PresetGCP, Format: FormatText},
+		{AppName: "test", Preset: PresetGCP, IncludeSeverityNumber: true},
+	} {
+		if _, err := resolveConfig(bad); err == nil {
+			t.Errorf("expected an error for %+v", *bad)
+		}
+	}
+}
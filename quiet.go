@@ -0,0 +1,44 @@
+// quiet.go
+//
+// # Chronos Logging - Quiet Until Error
+//
+// Implements `Config.QuietUntilError`: entries below ERROR are buffered in
+// memory and only emitted if an error occurs, giving failed runs their full
+// context while keeping successful runs silent.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+// quietBufferSize bounds the number of entries held back in quiet mode. When
+// full, the oldest entry is discarded.
+const quietBufferSize = 1000
+
+// quiet buffers entries below ERROR until the first error, then flushes the
+// buffer and emits everything from that point on.
+func (l *Logging) quiet(log Log) {
+	l.quietMu.Lock()
+	defer l.quietMu.Unlock()
+
+	if l.quietEnded {
+		l.emit(log)
+		return
+	}
+	if logLevels()[log.Level] < logLevels()[ERROR] {
+		if len(l.quietBuf) >= quietBufferSize {
+			l.quietBuf = l.quietBuf[1:]
+		}
+		l.quietBuf = append(l.quietBuf, log)
+		return
+	}
+
+	l.quietEnded = true
+	for _, buffered := range l.quietBuf {
+		l.emit(buffered)
+	}
+	l.quietBuf = nil
+	l.emit(log)
+}
@@ -0,0 +1,75 @@
+// quiet_test.go
+//
+// # Chronos Logging - Quiet Until Error Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import "testing"
+
+// TestQuietUntilErrorCleanRun asserts nothing is emitted when no error occurs.
+func TestQuietUntilErrorCleanRun(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.QuietUntilError = true
+	logger.Store(newLogging(cfg, logLevels()[DEBUG]))
+	defer Stop()
+
+	Debug("debug detail")
+	Info("progress")
+	Warn("minor issue")
+
+	if len(logger.Load().logChan) != 0 {
+		t.Errorf("expected no output on a clean run, got %d entries", len(logger.Load().logChan))
+	}
+}
+
+// TestQuietUntilErrorFlush asserts an error dumps the preceding entries in
+// order, then streams normally.
+func TestQuietUntilErrorFlush(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.QuietUntilError = true
+	logger.Store(newLogging(cfg, logLevels()[DEBUG]))
+	defer Stop()
+
+	Debug("debug detail")
+	Info("progress")
+	Error("failed")
+	Info("after")
+
+	want := []string{"debug detail", "progress", "failed", "after"}
+	if len(logger.Load().logChan) != len(want) {
+		t.Fatalf("expected %d entries, got %d", len(want), len(logger.Load().logChan))
+	}
+	for _, msg := range want {
+		got := <-logger.Load().logChan
+		if got.Message != msg {
+			t.Errorf("expected %q, got %q", msg, got.Message)
+		}
+	}
+}
+
+// TestQuietUntilErrorBounded ensures the buffer discards the oldest entries
+// once full.
+func TestQuietUntilErrorBounded(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.QuietUntilError = true
+	logger.Store(newLogging(cfg, logLevels()[DEBUG]))
+	defer Stop()
+
+	for i := 0; i < quietBufferSize+5; i++ {
+		Infof("entry %d", i)
+	}
+	if len(logger.Load().quietBuf) != quietBufferSize {
+		t.Fatalf("expected buffer of %d, got %d", quietBufferSize, len(logger.Load().quietBuf))
+	}
+	if logger.Load().quietBuf[0].Message != "entry 5" {
+		t.Errorf("expected oldest entries dropped, first is %q", logger.Load().quietBuf[0].Message)
+	}
+}
@@ -0,0 +1,97 @@
+// recover.go
+//
+// # Chronos Logging - Panic Recovery
+//
+// RecoverAndLog turns a panic into an ERROR entry instead of a crash. The
+// recovered value is kept as structured fields rather than flattened to a
+// string: an error gives its message, type and the messages of the errors
+// it wraps; a fmt.Stringer its string; a struct its JSON encoding.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// RecoverAndLog recovers a panic in the calling goroutine and logs it at
+// ERROR, waiting for the entry to be written. It must be deferred directly:
+//
+//	defer chronos.RecoverAndLog()
+//
+// The panic is not re-raised, so the deferring function returns normally.
+func RecoverAndLog() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	l := logger.Load()
+	if l == nil || !l.enabled(ERROR) {
+		return
+	}
+	l.addLog(Log{
+		TimeStamp: clock(),
+		Level:     ERROR,
+		Message:   fmt.Sprintf("recovered panic: %v", r),
+		Fields:    panicFields(r),
+	})
+	l.flush()
+}
+
+// panicFields describes a recovered panic value as fields.
+func panicFields(r interface{}) Fields {
+	switch v := r.(type) {
+	case error:
+		fields := Fields{
+			"error":      v.Error(),
+			"error_type": fmt.Sprintf("%T", v),
+		}
+		if chain := errorChain(v); len(chain) > 0 {
+			fields["error_chain"] = chain
+		}
+		return fields
+	case fmt.Stringer:
+		return Fields{"panic": v.String()}
+	}
+	rv := reflect.ValueOf(r)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Struct {
+		if data, err := json.Marshal(r); err == nil {
+			return Fields{"panic": json.RawMessage(data), "panic_type": fmt.Sprintf("%T", r)}
+		}
+	}
+	return Fields{"panic": fmt.Sprint(r)}
+}
+
+// errorChain returns the messages of the errors wrapped by err, depth first,
+// not including err itself.
+func errorChain(err error) []string {
+	var chain []string
+	var walk func(error)
+	walk = func(err error) {
+		var wrapped []error
+		switch e := err.(type) {
+		case interface{ Unwrap() []error }:
+			wrapped = e.Unwrap()
+		default:
+			if next := errors.Unwrap(err); next != nil {
+				wrapped = []error{next}
+			}
+		}
+		for _, w := range wrapped {
+			chain = append(chain, w.Error())
+			walk(w)
+		}
+	}
+	walk(err)
+	return chain
+}
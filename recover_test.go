@@ -0,0 +1,90 @@
+// recover_test.go
+//
+// # Chronos Logging - Recover Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validationError is a custom error type wrapping a cause.
+type validationError struct {
+	Field string
+	Err   error
+}
+
+func (e *validationError) Error() string { return fmt.Sprintf("invalid %s: %v", e.Field, e.Err) }
+func (e *validationError) Unwrap() error { return e.Err }
+
+// TestRecoverAndLog panics with a custom error, and then with a struct, and
+// asserts each is logged as structured JSON fields.
+func TestRecoverAndLog(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.Format = FormatJSON
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+	path := PathFor(time.Now())
+
+	cause := errors.New("must not be empty")
+	func() {
+		defer RecoverAndLog()
+		panic(&validationError{Field: "name", Err: cause})
+	}()
+	func() {
+		defer RecoverAndLog()
+		panic(struct{ Code int }{7})
+	}()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %q", data)
+	}
+
+	var got struct {
+		Level      string
+		Msg        string
+		Error      string
+		ErrorType  string   `json:"error_type"`
+		ErrorChain []string `json:"error_chain"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", lines[0], err)
+	}
+	if got.Level != ERROR || got.Error != "invalid name: must not be empty" {
+		t.Errorf("unexpected entry: %q", lines[0])
+	}
+	if got.ErrorType != "*chronos.validationError" {
+		t.Errorf("expected error type, got %q", got.ErrorType)
+	}
+	if len(got.ErrorChain) != 1 || got.ErrorChain[0] != cause.Error() {
+		t.Errorf("expected error chain [%s], got %v", cause, got.ErrorChain)
+	}
+
+	var structured struct {
+		Panic struct{ Code int }
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &structured); err != nil || structured.Panic.Code != 7 {
+		t.Errorf("expected struct panic as a JSON field, got %q (%v)", lines[1], err)
+	}
+}
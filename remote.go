@@ -0,0 +1,449 @@
+// remote.go
+//
+// # Chronos Logging - Remote Sinks
+//
+// Defines the `RemoteSink` interface for network destinations and the retry
+// buffer that governs their resilience. The writer hands each entry to every
+// remote sink's own queue without waiting, and each sink sends from its own
+// goroutine, so a slow sink never holds up the files or other sinks. Failed
+// sends are parked in a bounded in-memory queue per sink and retried with
+// exponential backoff. With `Config.RemoteTimeout`, each send is cancelled
+// once it takes longer than the timeout and treated as failed. Entries that
+// do not fit in a sink's queue, the oldest retry when the retry queue is
+// full, and entries that exhaust their retries are dropped; all are counted
+// (see RemoteDropped).
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RemoteSink delivers entries to a network destination such as a webhook or
+// syslog server. Send should return an error for transient failures so the
+// entry can be retried.
+type RemoteSink interface {
+	Send(ctx context.Context, log Log) error
+}
+
+// SinkConfig attaches a remote sink with a routing rule. Only entries
+// accepted by Match are sent to Sink.
+type SinkConfig struct {
+	Sink  RemoteSink `json:"-"`
+	Match Match      `json:"match"`
+}
+
+// Match is a declarative routing rule. An entry matches when its level is at
+// least Level (if set) and, for every key in Fields, the entry has that field
+// with a value whose fmt.Sprint form equals the given string. The zero Match
+// accepts every entry.
+type Match struct {
+	Level  string            `json:"level,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// matches reports whether log satisfies m. m.Level must already be a
+// canonical level name (Init normalizes it).
+func (m Match) matches(log Log) bool {
+	if m.Level != "" && logLevels()[log.Level] < logLevels()[m.Level] {
+		return false
+	}
+	for k, want := range m.Fields {
+		v, ok := log.Fields[k]
+		if !ok || fmt.Sprint(v) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// RemoteBuffer configures retries for failed remote sends. Zero values, and a
+// nil MaxRetries, select the defaults.
+type RemoteBuffer struct {
+	// Size bounds, per sink, the entries waiting to be sent and those
+	// waiting to be retried. A full send queue drops new entries; a full
+	// retry queue drops its oldest entry. Defaults to 1000.
+	Size int `json:"size"`
+
+	// MaxRetries is the number of retries after the initial failed send
+	// before an entry is dropped. Defaults to 3 when nil; point it at 0 to
+	// drop an entry as soon as a send fails.
+	MaxRetries *int `json:"max_retries,omitempty"`
+
+	// Backoff is the delay before the first retry; it doubles on each
+	// subsequent attempt. Defaults to 500ms.
+	Backoff time.Duration `json:"backoff"`
+}
+
+const (
+	defaultRemoteBufferSize = 1000
+	defaultRemoteMaxRetries = 3
+	defaultRemoteBackoff    = 500 * time.Millisecond
+)
+
+// withDefaults returns b with unset values replaced by the defaults.
+// MaxRetries is always set in the result, and not shared with b.
+func (b RemoteBuffer) withDefaults() RemoteBuffer {
+	if b.Size <= 0 {
+		b.Size = defaultRemoteBufferSize
+	}
+	retries := defaultRemoteMaxRetries
+	if b.MaxRetries != nil {
+		retries = *b.MaxRetries
+	}
+	b.MaxRetries = &retries
+	if b.Backoff <= 0 {
+		b.Backoff = defaultRemoteBackoff
+	}
+	return b
+}
+
+// remoteRetry is a failed send waiting to be retried. attempts counts the
+// sends made so far, including the initial one.
+type remoteRetry struct {
+	log      Log
+	attempts int
+	due      time.Time
+}
+
+// remoteDispatcher fans entries out to the remote sinks. Each sink has its
+// own queue and goroutine, so a slow sink delays only itself: the writer
+// never waits on a send, and entries that do not fit in a sink's queue are
+// dropped and counted. The sink list is owned by the writer goroutine.
+type remoteDispatcher struct {
+	workers []*sinkWorker
+	cfg     RemoteBuffer
+	timeout time.Duration
+	onError func(error)
+	dropped *atomic.Uint64
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// sinkWorker sends entries to one sink and retries its failed sends. id is
+// set for sinks attached with AddSink. done is closed when run returns.
+type sinkWorker struct {
+	id      string
+	target  SinkConfig
+	cfg     RemoteBuffer
+	timeout time.Duration
+	onError func(error)
+	dropped *atomic.Uint64
+	ctx     context.Context
+	entries chan Log
+	done    chan struct{}
+
+	// Owned by run().
+	retries []*remoteRetry
+}
+
+// newRemoteDispatcher starts a goroutine per sink. Sends taking longer than
+// timeout (if positive) are cancelled. Dropped entries are added to dropped.
+func newRemoteDispatcher(sinks []SinkConfig, cfg RemoteBuffer, timeout time.Duration, onError func(error), dropped *atomic.Uint64) *remoteDispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &remoteDispatcher{
+		cfg:     cfg.withDefaults(),
+		timeout: timeout,
+		onError: onError,
+		dropped: dropped,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	for _, target := range sinks {
+		d.add("", target)
+	}
+	return d
+}
+
+// add starts a worker for target under id ("" for configured sinks).
+func (d *remoteDispatcher) add(id string, target SinkConfig) {
+	w := &sinkWorker{
+		id:      id,
+		target:  target,
+		cfg:     d.cfg,
+		timeout: d.timeout,
+		onError: d.onError,
+		dropped: d.dropped,
+		ctx:     d.ctx,
+		entries: make(chan Log, d.cfg.Size),
+		done:    make(chan struct{}),
+	}
+	d.workers = append(d.workers, w)
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		defer close(w.done)
+		w.run()
+	}()
+}
+
+// find returns the worker added under id, or nil.
+func (d *remoteDispatcher) find(id string) *sinkWorker {
+	for _, w := range d.workers {
+		if id != "" && w.id == id {
+			return w
+		}
+	}
+	return nil
+}
+
+// detach removes the worker added under id and closes its queue, so it
+// exits once it has sent the entries already queued. It returns the worker,
+// or nil if there is none.
+func (d *remoteDispatcher) detach(id string) *sinkWorker {
+	for i, w := range d.workers {
+		if id != "" && w.id == id {
+			d.workers = append(d.workers[:i:i], d.workers[i+1:]...)
+			close(w.entries)
+			return w
+		}
+	}
+	return nil
+}
+
+// deliver queues log for every sink whose Match accepts it, without
+// blocking. A sink whose queue is full drops the entry.
+func (d *remoteDispatcher) deliver(log Log) {
+	for _, w := range d.workers {
+		if !w.target.Match.matches(log) {
+			continue
+		}
+		select {
+		case w.entries <- log:
+		default:
+			d.dropped.Add(1)
+		}
+	}
+}
+
+// close lets each sink send the entries already queued, then stops
+// retrying and closes the sinks that implement io.Closer. Entries still
+// waiting for a retry are counted as dropped.
+func (d *remoteDispatcher) close() {
+	for _, w := range d.workers {
+		close(w.entries)
+	}
+	d.wg.Wait()
+	d.cancel()
+	for _, w := range d.workers {
+		if c, ok := w.target.Sink.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				d.onError(fmt.Errorf("remote sink close failed: %w", err))
+			}
+		}
+	}
+}
+
+// run sends queued entries and retries failures as they become due, until
+// the queue is closed and drained.
+func (w *sinkWorker) run() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		wait := time.Hour
+		if next := w.nextDue(); !next.IsZero() {
+			wait = time.Until(next)
+		}
+		timer.Reset(wait)
+		select {
+		case log, ok := <-w.entries:
+			if !ok {
+				w.dropped.Add(uint64(len(w.retries)))
+				w.retries = nil
+				return
+			}
+			if err := w.send(log); err != nil {
+				if *w.cfg.MaxRetries == 0 {
+					w.dropped.Add(1)
+					w.onError(fmt.Errorf("remote sink dropped entry: %w", err))
+					continue
+				}
+				w.onError(fmt.Errorf("remote sink send failed, will retry: %w", err))
+				w.enqueue(&remoteRetry{log: log, attempts: 1, due: time.Now().Add(w.cfg.Backoff)})
+			}
+		case <-timer.C:
+			for _, r := range w.takeDue(time.Now()) {
+				w.retry(r)
+			}
+		case <-w.ctx.Done():
+			return
+		}
+	}
+}
+
+// send passes log to the sink, bounded by the configured timeout.
+func (w *sinkWorker) send(log Log) error {
+	if w.timeout <= 0 {
+		return w.target.Sink.Send(w.ctx, log)
+	}
+	ctx, cancel := context.WithTimeout(w.ctx, w.timeout)
+	defer cancel()
+	return w.target.Sink.Send(ctx, log)
+}
+
+// enqueue adds r to the retry queue, dropping the oldest entry when full.
+func (w *sinkWorker) enqueue(r *remoteRetry) {
+	if len(w.retries) >= w.cfg.Size {
+		w.retries = w.retries[1:]
+		w.dropped.Add(1)
+	}
+	w.retries = append(w.retries, r)
+}
+
+// nextDue returns when the earliest retry becomes due (zero if none).
+func (w *sinkWorker) nextDue() time.Time {
+	var next time.Time
+	for _, r := range w.retries {
+		if next.IsZero() || r.due.Before(next) {
+			next = r.due
+		}
+	}
+	return next
+}
+
+// takeDue removes and returns the retries due at now.
+func (w *sinkWorker) takeDue(now time.Time) []*remoteRetry {
+	var due []*remoteRetry
+	kept := w.retries[:0]
+	for _, r := range w.retries {
+		if !r.due.After(now) {
+			due = append(due, r)
+			continue
+		}
+		kept = append(kept, r)
+	}
+	w.retries = kept
+	return due
+}
+
+// retry attempts one queued send, requeueing it with a doubled backoff or
+// dropping it once MaxRetries is exhausted.
+func (w *sinkWorker) retry(r *remoteRetry) {
+	err := w.send(r.log)
+	r.attempts++
+	if err == nil {
+		return
+	}
+	if r.attempts > *w.cfg.MaxRetries {
+		w.dropped.Add(1)
+		w.onError(fmt.Errorf("remote sink dropped entry after %d retries: %w", *w.cfg.MaxRetries, err))
+		return
+	}
+	r.due = time.Now().Add(w.cfg.Backoff << (r.attempts - 1))
+	w.enqueue(r)
+}
+
+// remoteSinks returns the configured remote sinks: RemoteSinks, which accept
+// every entry, followed by Sinks with their routing rules.
+func (l *Logging) remoteSinks() []SinkConfig {
+	sinks := make([]SinkConfig, 0, len(l.config.RemoteSinks)+len(l.config.Sinks))
+	for _, sink := range l.config.RemoteSinks {
+		sinks = append(sinks, SinkConfig{Sink: sink})
+	}
+	return append(sinks, l.config.Sinks...)
+}
+
+// RemoteDropped returns the number of entries the running logger has dropped
+// for remote sinks because the retry buffer was full or retries ran out.
+func RemoteDropped() uint64 {
+	mu.Lock()
+	defer mu.Unlock()
+	l := logger.Load()
+	if l == nil {
+		return 0
+	}
+	return l.remoteDropped.Load()
+}
+
+// validateSink checks the sink configuration called name and canonicalizes
+// its Match level.
+func validateSink(name string, sink *SinkConfig) error {
+	if sink.Sink == nil {
+		return fmt.Errorf("%s.Sink is required", name)
+	}
+	if sink.Match.Level == "" {
+		return nil
+	}
+	level, ok := parseLevel(sink.Match.Level)
+	if !ok {
+		return fmt.Errorf("invalid log level in %s.Match: %s", name, sink.Match.Level)
+	}
+	sink.Match.Level = level
+	return nil
+}
+
+// AddSink attaches a remote sink to the running logger under id, for
+// example to enable a webhook at runtime. It receives the matching entries
+// logged after AddSink returns, with the same queueing and retries as
+// configured sinks. id must not be empty or already in use.
+func AddSink(id string, cfg SinkConfig) error {
+	if id == "" {
+		return errors.New("sink id is required")
+	}
+	if err := validateSink("sink "+id, &cfg); err != nil {
+		return err
+	}
+	return sinkControl(func(l *Logging) error {
+		if l.remote == nil {
+			l.remote = newRemoteDispatcher(nil, l.config.RemoteBuffer, l.config.RemoteTimeout, l.reportError, &l.remoteDropped)
+		}
+		if l.remote.find(id) != nil {
+			return fmt.Errorf("sink %s already exists", id)
+		}
+		l.remote.add(id, cfg)
+		return nil
+	})
+}
+
+// RemoveSink detaches the sink added with AddSink under id. Entries logged
+// before the call are still sent to it; RemoveSink waits for those sends,
+// then closes the sink if it implements io.Closer. Entries still waiting
+// for a retry are dropped and counted.
+func RemoveSink(id string) error {
+	var w *sinkWorker
+	err := sinkControl(func(l *Logging) error {
+		if l.remote != nil {
+			w = l.remote.detach(id)
+		}
+		if w == nil {
+			return fmt.Errorf("no sink with id %s", id)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	<-w.done
+	if c, ok := w.target.Sink.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// sinkControl runs fn on the running logger's writer goroutine, in queue
+// order, and returns its error. mu is not held while queueing, since a full
+// queue blocks; if Stop runs first, submit reports errStopped instead.
+func sinkControl(fn func(l *Logging) error) error {
+	mu.Lock()
+	l := logger.Load()
+	mu.Unlock()
+	if l == nil {
+		return errors.New("logger not initialized")
+	}
+	done := make(chan error, 1)
+	l.submit(Log{done: done, control: func() error { return fn(l) }})
+	return <-done
+}
@@ -0,0 +1,440 @@
+// remote_test.go
+//
+// # Chronos Logging - Remote Sink Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakySink fails the first failures sends and records successful ones.
+type flakySink struct {
+	mu        sync.Mutex
+	failures  int
+	attempts  int
+	delivered []Log
+}
+
+func (s *flakySink) Send(ctx context.Context, log Log) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if s.attempts <= s.failures {
+		return errors.New("transient failure")
+	}
+	s.delivered = append(s.delivered, log)
+	return nil
+}
+
+func (s *flakySink) snapshot() (int, []Log) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts, append([]Log(nil), s.delivered...)
+}
+
+// retries returns a pointer to n for RemoteBuffer.MaxRetries.
+func retries(n int) *int {
+	return &n
+}
+
+// startRemoteLogger starts a logger with the given remote sinks and retry
+// settings, returning a function that stops it and waits for the writer.
+func startRemoteLogger(t *testing.T, buf RemoteBuffer, sinks ...RemoteSink) func() {
+	t.Helper()
+	Stop()
+	tempDir, err := os.MkdirTemp("", "remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.RemoteSinks = sinks
+	cfg.RemoteBuffer = buf
+	cfg.ErrorHandler = func(error) {}
+	l := newLogging(cfg, logLevels()[INFO])
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+	return func() {
+		Stop()
+		<-done
+		os.RemoveAll(tempDir)
+	}
+}
+
+// TestRemoteSinkRetriesUntilDelivered uses a sink that fails twice then
+// succeeds and asserts the entry is eventually delivered.
+func TestRemoteSinkRetriesUntilDelivered(t *testing.T) {
+	sink := &flakySink{failures: 2}
+	stop := startRemoteLogger(t, RemoteBuffer{MaxRetries: retries(3), Backoff: 10 * time.Millisecond}, sink)
+	defer stop()
+
+	Info("eventually delivered")
+	ok := waitFor(t, 2*time.Second, func() bool {
+		_, delivered := sink.snapshot()
+		return len(delivered) == 1
+	})
+	if !ok {
+		t.Fatal("expected entry to be delivered after retries")
+	}
+	attempts, delivered := sink.snapshot()
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	if delivered[0].Message != "eventually delivered" {
+		t.Errorf("unexpected entry %+v", delivered[0])
+	}
+	if RemoteDropped() != 0 {
+		t.Errorf("expected no drops, got %d", RemoteDropped())
+	}
+}
+
+// TestRemoteSinkDropsAfterMaxRetries asserts an entry is dropped and counted
+// once its retries are exhausted.
+func TestRemoteSinkDropsAfterMaxRetries(t *testing.T) {
+	sink := &flakySink{failures: 100}
+	stop := startRemoteLogger(t, RemoteBuffer{MaxRetries: retries(2), Backoff: 5 * time.Millisecond}, sink)
+	defer stop()
+
+	Info("never delivered")
+	if !waitFor(t, 2*time.Second, func() bool { return RemoteDropped() == 1 }) {
+		t.Fatalf("expected 1 drop, got %d", RemoteDropped())
+	}
+	if attempts, _ := sink.snapshot(); attempts != 3 {
+		t.Errorf("expected initial send plus 2 retries, got %d attempts", attempts)
+	}
+}
+
+// TestRemoteSinkNoRetries asserts MaxRetries of 0 drops an entry on its
+// first failed send, while an unset MaxRetries keeps the default.
+func TestRemoteSinkNoRetries(t *testing.T) {
+	if got := *(RemoteBuffer{}).withDefaults().MaxRetries; got != defaultRemoteMaxRetries {
+		t.Errorf("expected %d retries by default, got %d", defaultRemoteMaxRetries, got)
+	}
+
+	sink := &flakySink{failures: 100}
+	stop := startRemoteLogger(t, RemoteBuffer{MaxRetries: retries(0), Backoff: 5 * time.Millisecond}, sink)
+	defer stop()
+
+	Info("never delivered")
+	if !waitFor(t, 2*time.Second, func() bool { return RemoteDropped() == 1 }) {
+		t.Fatalf("expected 1 drop, got %d", RemoteDropped())
+	}
+	time.Sleep(50 * time.Millisecond)
+	if attempts, _ := sink.snapshot(); attempts != 1 {
+		t.Errorf("expected only the initial send, got %d attempts", attempts)
+	}
+}
+
+// TestRemoteBufferDropsOldest asserts the retry queue drops the oldest entry
+// when full.
+func TestRemoteBufferDropsOldest(t *testing.T) {
+	sink := &flakySink{failures: 100}
+	stop := startRemoteLogger(t, RemoteBuffer{Size: 2, MaxRetries: retries(3), Backoff: time.Hour}, sink)
+	defer stop()
+
+	Info("one")
+	Info("two")
+	Info("three")
+	if !waitFor(t, time.Second, func() bool { return RemoteDropped() == 1 }) {
+		t.Fatalf("expected 1 drop, got %d", RemoteDropped())
+	}
+}
+
+// TestSinkMatch routes entries with Match rules: one sink accepts only
+// env=prod, another only ERROR and above with env=prod, and a file keeps
+// everything.
+func TestSinkMatch(t *testing.T) {
+	Stop()
+	tempDir, err := os.MkdirTemp("", "match")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	prod := &flakySink{}
+	prodErrors := &flakySink{}
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.Sinks = []SinkConfig{
+		{Sink: prod, Match: Match{Fields: map[string]string{"env": "prod"}}},
+		{Sink: prodErrors, Match: Match{Level: "error", Fields: map[string]string{"env": "prod"}}},
+	}
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	WithFields(Fields{"env": "prod"}).Info("prod info")
+	WithFields(Fields{"env": "dev"}).Error("dev error")
+	WithFields(Fields{"env": "prod"}).Error("prod error")
+	Error("no env")
+	// Sinks are sent to asynchronously; give both time to catch up.
+	waitFor(t, time.Second, func() bool {
+		_, a := prod.snapshot()
+		_, b := prodErrors.snapshot()
+		return len(a) >= 2 && len(b) >= 1
+	})
+	Stop()
+
+	_, got := prod.snapshot()
+	if len(got) != 2 || got[0].Message != "prod info" || got[1].Message != "prod error" {
+		t.Errorf("unexpected entries for env=prod sink: %+v", got)
+	}
+	_, got = prodErrors.snapshot()
+	if len(got) != 1 || got[0].Message != "prod error" {
+		t.Errorf("unexpected entries for ERROR env=prod sink: %+v", got)
+	}
+
+	cfg = getConfig()
+	cfg.Sinks = []SinkConfig{{Sink: prod, Match: Match{Level: "loud"}}}
+	if err := Init(cfg); err == nil {
+		Stop()
+		t.Error("expected invalid Match level to be rejected")
+	}
+}
+
+// slowSink blocks each send until its context is done and records why.
+type slowSink struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (s *slowSink) Send(ctx context.Context, log Log) error {
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errs = append(s.errs, ctx.Err())
+	return ctx.Err()
+}
+
+func (s *slowSink) attempts() []error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]error(nil), s.errs...)
+}
+
+// TestRemoteTimeout uses a sink that outlasts RemoteTimeout and asserts
+// every send is cancelled at the deadline, fails, and is finally dropped.
+func TestRemoteTimeout(t *testing.T) {
+	sink := &slowSink{}
+	Stop()
+	tempDir, err := os.MkdirTemp("", "remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.RemoteSinks = []RemoteSink{sink}
+	cfg.RemoteBuffer = RemoteBuffer{MaxRetries: retries(1), Backoff: 5 * time.Millisecond}
+	cfg.RemoteTimeout = 20 * time.Millisecond
+	cfg.ErrorHandler = func(error) {}
+	l := newLogging(cfg, logLevels()[INFO])
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+	defer func() {
+		Stop()
+		<-done
+	}()
+
+	start := time.Now()
+	Info("too slow")
+	if !waitFor(t, 2*time.Second, func() bool { return RemoteDropped() == 1 }) {
+		t.Fatalf("expected the timed out entry to be dropped, got %d drops", RemoteDropped())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected sends to be cancelled at the timeout, took %v", elapsed)
+	}
+	errs := sink.attempts()
+	if len(errs) != 2 {
+		t.Fatalf("expected initial send plus 1 retry, got %d attempts", len(errs))
+	}
+	for _, err := range errs {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected deadline exceeded, got %v", err)
+		}
+	}
+}
+
+// blockingSink holds every send until release is closed.
+type blockingSink struct {
+	release chan struct{}
+	sent    atomic.Int64
+}
+
+func (s *blockingSink) Send(ctx context.Context, log Log) error {
+	select {
+	case <-s.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	s.sent.Add(1)
+	return nil
+}
+
+// TestSlowSinkIsolated pairs a sink that never returns with a fast one and
+// asserts the log file and the fast sink keep up while the slow sink's queue
+// fills and overflowing entries are dropped.
+func TestSlowSinkIsolated(t *testing.T) {
+	slow := &blockingSink{release: make(chan struct{})}
+	fast := &flakySink{}
+	stop := startRemoteLogger(t, RemoteBuffer{Size: 10}, slow, fast)
+	defer stop()
+	defer close(slow.release)
+
+	// Log in bursts no larger than a queue so only the slow sink overflows.
+	const n = 50
+	for i := 0; i < n; i++ {
+		Infof("entry %d", i)
+		if (i+1)%10 != 0 {
+			continue
+		}
+		if !waitFor(t, 2*time.Second, func() bool {
+			_, delivered := fast.snapshot()
+			return len(delivered) == i+1
+		}) {
+			_, delivered := fast.snapshot()
+			t.Fatalf("expected the fast sink to receive %d entries, got %d", i+1, len(delivered))
+		}
+	}
+	logger.Load().flush()
+	data, err := os.ReadFile(PathFor(clock()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != n {
+		t.Errorf("expected %d lines in the log file, got %d", n, lines)
+	}
+	// Ten entries wait in the slow sink's queue, plus the one held by the
+	// blocked send if the worker picked it up before the queue filled.
+	if got := RemoteDropped(); got != n-11 && got != n-10 {
+		t.Errorf("expected %d or %d drops for the slow sink, got %d", n-11, n-10, got)
+	}
+	if got := slow.sent.Load(); got != 0 {
+		t.Errorf("expected the slow sink to be stuck, sent %d", got)
+	}
+}
+
+// closingSink records deliveries and whether it was closed.
+type closingSink struct {
+	flakySink
+	closed atomic.Bool
+}
+
+func (s *closingSink) Close() error {
+	s.closed.Store(true)
+	return nil
+}
+
+// TestAddRemoveSink attaches a sink at runtime, logs, removes it, logs
+// again, and asserts the sink received only the first batch and was closed.
+func TestAddRemoveSink(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	sink := &closingSink{}
+	if err := AddSink("webhook", SinkConfig{Sink: sink, Match: Match{Level: "warn"}}); err != nil {
+		t.Fatalf("AddSink failed: %v", err)
+	}
+	if err := AddSink("webhook", SinkConfig{Sink: &flakySink{}}); err == nil {
+		t.Error("expected a duplicate id to be rejected")
+	}
+	Info("below the match level")
+	Warn("first")
+	Error("second")
+	if err := RemoveSink("webhook"); err != nil {
+		t.Fatalf("RemoveSink failed: %v", err)
+	}
+	if !sink.closed.Load() {
+		t.Error("expected the removed sink to be closed")
+	}
+	Warn("after removal")
+	if err := RemoveSink("webhook"); err == nil {
+		t.Error("expected removing an unknown sink to fail")
+	}
+	logger.Load().flush()
+
+	_, got := sink.snapshot()
+	if len(got) != 2 || got[0].Message != "first" || got[1].Message != "second" {
+		t.Errorf("expected only the first batch, got %+v", got)
+	}
+}
+
+// TestRemoteSinkClosedOnStop asserts a remote sink implementing io.Closer is
+// closed once the logger stops, after it has been sent every entry.
+func TestRemoteSinkClosedOnStop(t *testing.T) {
+	sink := &closingSink{}
+	stop := startRemoteLogger(t, RemoteBuffer{}, sink)
+	Info("last words")
+	stop()
+
+	if !sink.closed.Load() {
+		t.Fatal("expected the sink to be closed on Stop")
+	}
+	if _, got := sink.snapshot(); len(got) != 1 || got[0].Message != "last words" {
+		t.Errorf("expected the entry sent before Close, got %v", got)
+	}
+}
+
+// TestSinkControlFullQueue blocks AddSink on a full queue and asserts it does
+// not hold mu while it waits.
+func TestSinkControlFullQueue(t *testing.T) {
+	Stop()
+	fs := newMemFS()
+	l := newLogging(getConfig(), logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	for len(l.logChan) < cap(l.logChan) {
+		l.logChan <- Log{TimeStamp: time.Now(), Level: INFO, Message: "queued"}
+	}
+
+	added := make(chan error, 1)
+	go func() {
+		added <- AddSink("mem", SinkConfig{Sink: &flakySink{}})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	if !mu.TryLock() {
+		t.Fatal("expected mu to be free while AddSink waits")
+	}
+	mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+	if err := <-added; err != nil {
+		t.Errorf("AddSink failed: %v", err)
+	}
+	Stop()
+	<-done
+}
@@ -0,0 +1,101 @@
+// retention.go
+//
+// # Chronos Logging - Retention Classes
+//
+// Entries can be tagged with a retention class (see InfoRetention). Tagged
+// entries are written to files in a subdirectory named after the class, and
+// the writer deletes files there once they are older than the class's
+// maximum age in `Config.Retention`.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// validRetentionClass reports whether class can be used as a subdirectory
+// name for a retention class.
+func validRetentionClass(class string) bool {
+	return class != "" && class != "." && class != ".." && !strings.ContainsAny(class, `/\`)
+}
+
+// InfoRetention logs a message at INFO level tagged with a retention class.
+// The entry is written to the class's subdirectory of `Config.Location`,
+// where files are kept for the class's maximum age in `Config.Retention`
+// (indefinitely if the class has none). An invalid class name is reported
+// and the entry is logged untagged.
+func InfoRetention(class, msg string) {
+	l := logger.Load()
+	if l == nil || !l.enabled(INFO) {
+		return
+	}
+	if !validRetentionClass(class) {
+		l.reportError(fmt.Errorf("invalid retention class: %q", class))
+		class = ""
+	}
+	log := Log{
+		TimeStamp: clock(),
+		Level:     INFO,
+		Message:   msg,
+		retention: class,
+	}
+	l.addLog(log)
+}
+
+// sweep deletes files in retention class directories whose modification
+// time is older than the class's maximum age, closing them first if the
+// writer still has them open. keep, the file just opened, is never deleted.
+// It runs when the writer starts and whenever it opens a file.
+func (l *Logging) sweep(keep string) {
+	now := clock()
+	for class, maxAge := range l.config.Retention {
+		if maxAge <= 0 {
+			continue
+		}
+		dir := filepath.Join(l.path, class)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				l.reportError(fmt.Errorf("could not read retention directory %s: %w", dir, err))
+			}
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if path == keep {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || now.Sub(info.ModTime()) <= maxAge {
+				continue
+			}
+			if key, ok := l.handleFor(path); ok {
+				l.closeFile(key)
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				l.reportError(fmt.Errorf("could not remove expired log file %s: %w", path, err))
+			}
+		}
+	}
+}
+
+// handleFor returns the key of the writer's open handle for path, if any.
+func (l *Logging) handleFor(path string) (string, bool) {
+	for key, h := range l.handles {
+		if h.path == path {
+			return key, true
+		}
+	}
+	return "", false
+}
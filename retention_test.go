@@ -0,0 +1,82 @@
+// retention_test.go
+//
+// # Chronos Logging - Retention Class Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRetentionSweep tags entries with a short and a long retention class
+// and advances the clock, asserting the short-lived file is swept first.
+func TestRetentionSweep(t *testing.T) {
+	Stop()
+	fc := &fakeClock{t: time.Now()}
+	defer useClock(fc)()
+
+	tempDir, err := os.MkdirTemp("", "retention")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.Retention = map[string]time.Duration{
+		"short": time.Hour,
+		"long":  24 * time.Hour,
+	}
+	l := newLogging(cfg, logLevels()[INFO])
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+	defer func() {
+		Stop()
+		<-done
+	}()
+
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+	name := l.filename(fc.Now())
+	short := filepath.Join(tempDir, "short", name)
+	long := filepath.Join(tempDir, "long", name)
+
+	InfoRetention("short", "debug session")
+	InfoRetention("long", "payment accepted")
+	l.flush()
+	if !exists(short) || !exists(long) {
+		t.Fatal("expected both class files to be written")
+	}
+
+	// Opening the next hour's file sweeps expired files.
+	fc.Advance(2 * time.Hour)
+	InfoRetention("long", "refund issued")
+	l.flush()
+	if exists(short) {
+		t.Error("expected the short retention file to be swept")
+	}
+	if !exists(long) {
+		t.Error("expected the long retention file to be kept")
+	}
+
+	fc.Advance(24 * time.Hour)
+	InfoRetention("long", "audit complete")
+	l.flush()
+	if exists(long) {
+		t.Error("expected the long retention file to be swept once expired")
+	}
+}
@@ -0,0 +1,115 @@
+// sample.go
+//
+// # Chronos Logging - Sampling
+//
+// Thins out repetitive entries. With `Config.Sampling`, the first
+// BurstAllowance occurrences of each message are logged, so a startup burst
+// is kept intact, and after that only every Thereafter-th occurrence is.
+// Occurrences are counted per level and message in a bounded table: once it
+// holds sampleKeys messages it is cleared and counting starts over, so
+// memory does not grow with the number of distinct messages and every
+// message still gets its burst.
+//
+// `Config.LevelSampling` gives levels their own rule. ERROR and more severe
+// levels are never sampled unless they have a rule there, so the general
+// Sampling rule cannot hide failures.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// sampleKeys is the number of distinct messages a sampler counts before it
+// starts over.
+const sampleKeys = 4096
+
+// SampleRule is a first-N-then-1-in-M sampling policy.
+type SampleRule struct {
+	// BurstAllowance is how many occurrences of each message are logged
+	// before sampling starts.
+	BurstAllowance int `json:"burst_allowance"`
+
+	// Thereafter logs every Thereafter-th occurrence once the allowance is
+	// used up. Zero drops every further occurrence.
+	Thereafter int `json:"thereafter"`
+}
+
+// sampleKey identifies the entries a sampler counts together.
+type sampleKey struct {
+	level   string
+	message string
+}
+
+// sampler applies the sampling rules. It is safe for concurrent use.
+type sampler struct {
+	rule    *SampleRule
+	levels  map[string]SampleRule
+	mu      sync.Mutex
+	counts  map[sampleKey]uint64
+	dropped atomic.Uint64
+}
+
+// newSampler returns a sampler applying levels to their levels and rule
+// (if not nil) to the rest.
+func newSampler(rule *SampleRule, levels map[string]SampleRule) *sampler {
+	return &sampler{rule: rule, levels: levels, counts: make(map[sampleKey]uint64)}
+}
+
+// ruleFor returns the rule for level: its own from LevelSampling, else the
+// general rule for levels below ERROR. ok is false if level is not sampled.
+func (s *sampler) ruleFor(level string) (rule SampleRule, ok bool) {
+	if rule, ok := s.levels[level]; ok {
+		return rule, true
+	}
+	if s.rule == nil || logLevels()[level] >= logLevels()[ERROR] {
+		return SampleRule{}, false
+	}
+	return *s.rule, true
+}
+
+// allow counts an occurrence of the entry's level and message and reports
+// whether it should be logged.
+func (s *sampler) allow(log Log) bool {
+	rule, ok := s.ruleFor(log.Level)
+	if !ok {
+		return true
+	}
+	key := sampleKey{log.Level, log.Message}
+	s.mu.Lock()
+	n, seen := s.counts[key]
+	if !seen && len(s.counts) >= sampleKeys {
+		clear(s.counts)
+	}
+	n++
+	s.counts[key] = n
+	s.mu.Unlock()
+
+	burst := uint64(rule.BurstAllowance)
+	if n <= burst {
+		return true
+	}
+	if rule.Thereafter > 0 && (n-burst-1)%uint64(rule.Thereafter) == 0 {
+		return true
+	}
+	s.dropped.Add(1)
+	return false
+}
+
+// Sampled returns how many entries the running logger has discarded by
+// sampling (see `Config.Sampling` and `Config.LevelSampling`).
+func Sampled() uint64 {
+	mu.Lock()
+	defer mu.Unlock()
+	l := logger.Load()
+	if l == nil || l.sampler == nil {
+		return 0
+	}
+	return l.sampler.dropped.Load()
+}
@@ -0,0 +1,130 @@
+// sample_test.go
+//
+// # Chronos Logging - Sampling Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSamplingBurstAllowance repeats a startup message and asserts the
+// first BurstAllowance occurrences all appear, then only every
+// Thereafter-th, while a different message is counted separately.
+func TestSamplingBurstAllowance(t *testing.T) {
+	Stop()
+	fs := newMemFS()
+	cfg := getConfig()
+	cfg.Sampling = &SampleRule{BurstAllowance: 5, Thereafter: 10}
+	l := newLogging(cfg, logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	now := time.Now()
+	for i := 1; i <= 50; i++ {
+		Infof("connecting to database")
+		WithFields(Fields{"n": i}).Info("worker started")
+	}
+	Info("ready")
+	sampled := Sampled()
+	Stop()
+	<-done
+
+	var got []string
+	for _, line := range strings.Split(strings.TrimSuffix(fs.file(l.filePathFor(now)).String(), "\n"), "\n") {
+		if strings.Contains(line, "worker started") {
+			got = append(got, line[strings.LastIndex(line, "\t")+1:])
+		}
+	}
+	var want []string
+	for _, n := range []int{1, 2, 3, 4, 5, 6, 16, 26, 36, 46} {
+		want = append(want, fmt.Sprintf("n=%d", n))
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected occurrences %v, got %v", want, got)
+	}
+	if sampled != 80 {
+		t.Errorf("expected 80 sampled entries, got %d", sampled)
+	}
+	if !strings.Contains(fs.file(l.filePathFor(now)).String(), "\tready\n") {
+		t.Error("expected a distinct message to be logged")
+	}
+}
+
+// TestSamplingDistinctMessages logs more distinct messages than a sampler
+// counts at once, each a single time, and asserts none is sampled.
+func TestSamplingDistinctMessages(t *testing.T) {
+	s := newSampler(&SampleRule{BurstAllowance: 1}, nil)
+	const n = 5000
+	for i := 0; i < n; i++ {
+		if !s.allow(Log{Level: INFO, Message: fmt.Sprintf("message %d", i)}) {
+			t.Fatalf("message %d was sampled on its first occurrence", i)
+		}
+	}
+	if len(s.counts) > sampleKeys {
+		t.Errorf("expected at most %d counters, got %d", sampleKeys, len(s.counts))
+	}
+	if s.allow(Log{Level: INFO, Message: fmt.Sprintf("message %d", n-1)}) {
+		t.Error("expected a repeated message to be sampled")
+	}
+}
+
+// TestLevelSampling floods INFO and ERROR with heavy INFO sampling and a
+// general rule in place, and asserts only INFO is sampled.
+func TestLevelSampling(t *testing.T) {
+	Stop()
+	tempDir, err := os.MkdirTemp("", "levelsampling")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.SyncForTest = true
+	cfg.Sampling = &SampleRule{BurstAllowance: 1}
+	cfg.LevelSampling = map[string]SampleRule{"info": {BurstAllowance: 2, Thereafter: 100}}
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	for i := 0; i < 100; i++ {
+		Info("polling")
+		Error("upstream unavailable")
+	}
+	content, err := os.ReadFile(PathFor(clock()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(content), "\tINFO\tpolling"); n != 3 {
+		t.Errorf("expected 3 INFO lines, got %d", n)
+	}
+	if n := strings.Count(string(content), "\tERROR\tupstream unavailable"); n != 100 {
+		t.Errorf("expected every ERROR line, got %d", n)
+	}
+	if got := Sampled(); got != 97 {
+		t.Errorf("expected 97 sampled entries, got %d", got)
+	}
+
+	cfg = getConfig()
+	cfg.LevelSampling = map[string]SampleRule{"loud": {}}
+	if err := Init(cfg); err == nil {
+		Stop()
+		t.Error("expected an unknown LevelSampling level to be rejected")
+	}
+}
@@ -0,0 +1,53 @@
+// sanitize.go
+//
+// # Chronos Logging - UTF-8 Sanitization
+//
+// Replaces invalid UTF-8 in messages and string field values before they
+// reach the console, files, or sinks, so raw bytes cannot corrupt terminals
+// or structured output.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// sanitizeUTF8 reports whether entries should be sanitized: when
+// `Config.SanitizeUTF8` is set, and always for the JSON format.
+func (l *Logging) sanitizeUTF8() bool {
+	return l.config.SanitizeUTF8 || l.config.Format == FormatJSON
+}
+
+// sanitize returns log with every invalid UTF-8 sequence in its message,
+// field keys, and string field values replaced by U+FFFD. The fields map is
+// copied only when something needs replacing.
+func sanitize(log Log) Log {
+	log.Message = strings.ToValidUTF8(log.Message, string(utf8.RuneError))
+
+	dirty := false
+	for k, v := range log.Fields {
+		s, isString := v.(string)
+		if !utf8.ValidString(k) || (isString && !utf8.ValidString(s)) {
+			dirty = true
+			break
+		}
+	}
+	if !dirty {
+		return log
+	}
+	fields := make(Fields, len(log.Fields))
+	for k, v := range log.Fields {
+		if s, ok := v.(string); ok {
+			v = strings.ToValidUTF8(s, string(utf8.RuneError))
+		}
+		fields[strings.ToValidUTF8(k, string(utf8.RuneError))] = v
+	}
+	log.Fields = fields
+	return log
+}
@@ -0,0 +1,56 @@
+// sanitize_test.go
+//
+// # Chronos Logging - UTF-8 Sanitization Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// TestSanitizeUTF8 logs a message and field containing invalid bytes and
+// asserts the text and JSON output is valid UTF-8 (and valid JSON).
+func TestSanitizeUTF8(t *testing.T) {
+	for _, format := range []LogFormat{FormatText, FormatJSON} {
+		Stop()
+		fs := newMemFS()
+		out := &syncBuffer{}
+		cfg := getConfig()
+		cfg.Format = format
+		cfg.SanitizeUTF8 = format == FormatText
+		l := newLogging(cfg, logLevels()[INFO])
+		l.opener = fs.open
+		l.console = newConsoleWriter(out, false)
+		logger.Store(l)
+		done := make(chan struct{})
+		go func() {
+			l.start()
+			close(done)
+		}()
+
+		now := time.Now()
+		WithFields(Fields{"raw": "ab\xc3\x28cd"}).Info("bad \xff\xfe bytes")
+		Stop()
+		<-done
+
+		content := fs.file(l.filePathFor(now)).String()
+		if !utf8.ValidString(content) || !utf8.ValidString(out.String()) {
+			t.Errorf("%s: expected valid UTF-8, got %q / %q", format, content, out.String())
+		}
+		if !strings.Contains(content, "bad � bytes") || !strings.Contains(content, "ab�(cd") {
+			t.Errorf("%s: expected replacement characters, got %q", format, content)
+		}
+		if format == FormatJSON && !json.Valid([]byte(strings.TrimSpace(content))) {
+			t.Errorf("expected valid JSON, got %q", content)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// selftest.go
+//
+// # Chronos Logging - Self Test
+//
+// SelfTest checks the logger is able to persist entries, for use in
+// readiness and health probes. It writes nothing to the logs.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// selfTestTimeout bounds each wait on the writer during SelfTest.
+const selfTestTimeout = 2 * time.Second
+
+// SelfTest reports whether the logger is healthy: it is initialized, the
+// current log directory is writable (checked with a temporary file that is
+// removed again; skipped when `Config.FS` is set, as it cannot remove
+// files), and the writer goroutine answers a sentinel sent through its queue
+// within a timeout. No log line is produced. A nil error means the logger is
+// ready.
+func SelfTest() error {
+	mu.Lock()
+	l := logger.Load()
+	mu.Unlock()
+	if l == nil {
+		return errors.New("logger not initialized")
+	}
+	if l.fifo == "" && l.file == nil && l.config.FS == nil {
+		dir := l.dirFor(clock())
+		f, err := os.CreateTemp(dir, ".chronos-selftest-*")
+		if err != nil {
+			return fmt.Errorf("log directory %s is not writable: %w", dir, err)
+		}
+		f.Close()
+		os.Remove(f.Name())
+	}
+
+	done := make(chan error, 1)
+	sentinel := Log{done: done, control: func() error { return nil }}
+	if l.config.SyncForTest {
+		l.inline(sentinel)
+		return <-done
+	}
+	timer := time.NewTimer(selfTestTimeout)
+	defer timer.Stop()
+	// sendMu is held while queueing so Stop cannot close the queue
+	// underneath the sentinel; Stop waits at most selfTestTimeout for it.
+	l.sendMu.RLock()
+	if l.stopped.Load() {
+		l.sendMu.RUnlock()
+		return errStopped
+	}
+	select {
+	case l.logChan <- sentinel:
+		l.sendMu.RUnlock()
+	case <-timer.C:
+		l.sendMu.RUnlock()
+		return errors.New("log writer queue is full")
+	}
+
+	timer.Reset(selfTestTimeout)
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+		return errors.New("log writer did not respond")
+	}
+}
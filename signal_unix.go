@@ -0,0 +1,85 @@
+// signal_unix.go
+//
+// # Chronos Logging - Signal Handling (Unix)
+//
+// Installs the SIGUSR1 handler used for logrotate integration, the SIGUSR2
+// handler that flushes buffered output, and the SIGPIPE handler that keeps a
+// closed stdout from killing the process.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+
+//go:build !windows
+
+package chronos
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// installReopenHandler asks the writer of l to reopen its log file whenever
+// the process receives SIGUSR1. The handler is removed when l is stopped.
+func installReopenHandler(l *Logging) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGUSR1)
+	go func() {
+		defer signal.Stop(sigc)
+		for {
+			select {
+			case <-sigc:
+				select {
+				case l.reopen <- struct{}{}:
+				default:
+					// A reopen is already pending.
+				}
+			case <-l.quit:
+				return
+			}
+		}
+	}()
+}
+
+// installFlushHandler flushes l whenever the process receives SIGUSR2: every
+// entry logged before the signal is written out to its file(s), without
+// rotating or stopping, so the files can be copied complete. The handler is
+// removed when l is stopped.
+func installFlushHandler(l *Logging) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGUSR2)
+	go func() {
+		defer signal.Stop(sigc)
+		for {
+			select {
+			case <-sigc:
+				l.flushIfRunning()
+			case <-l.quit:
+				return
+			}
+		}
+	}()
+}
+
+// installPipeHandler receives SIGPIPE while l runs, for
+// `Config.IgnoreSIGPIPE`. By default Go exits when a write to a broken stdout
+// pipe raises SIGPIPE; with the signal handled the write returns EPIPE
+// instead, which disables console output (see consoleWriter). The handler is
+// removed when l is stopped.
+func installPipeHandler(l *Logging) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGPIPE)
+	go func() {
+		defer signal.Stop(sigc)
+		for {
+			select {
+			case <-sigc:
+			case <-l.quit:
+				return
+			}
+		}
+	}()
+}
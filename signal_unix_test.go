@@ -0,0 +1,121 @@
+// signal_unix_test.go
+//
+// # Chronos Logging - Signal Handling Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+
+//go:build !windows
+
+package chronos
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestReopenOnSIGUSR1 simulates logrotate: the file is renamed, SIGUSR1 is
+// sent, and the next entry must land in a new file at the original path.
+func TestReopenOnSIGUSR1(t *testing.T) {
+	Stop()
+
+	tempDir, err := os.MkdirTemp("", "reopen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.ReopenOnSIGUSR1 = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	path := logger.Load().filePathFor(time.Now())
+	Info("before rotate")
+	time.Sleep(100 * time.Millisecond)
+
+	rotated := path + ".1"
+	if err := os.Rename(path, rotated); err != nil {
+		t.Fatalf("rename failed: %v", err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("failed to send SIGUSR1: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	Info("after rotate")
+	time.Sleep(100 * time.Millisecond)
+
+	fresh, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected new file at original path: %v", err)
+	}
+	if !strings.Contains(string(fresh), "after rotate") || strings.Contains(string(fresh), "before rotate") {
+		t.Errorf("unexpected content in new file: %q", fresh)
+	}
+	old, err := os.ReadFile(rotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(old), "after rotate") {
+		t.Errorf("rotated file received entries after reopen: %q", old)
+	}
+}
+
+// TestFlushOnSIGUSR2 buffers entries behind a long flush interval and checks
+// that SIGUSR2 writes them all out while the logger keeps running.
+func TestFlushOnSIGUSR2(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.FlushInterval = time.Hour
+	cfg.FlushOnSIGUSR2 = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	path := logger.Load().filePathFor(time.Now())
+	const n = 20
+	for i := 0; i < n; i++ {
+		Infof("entry %d", i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if data, _ := os.ReadFile(path); strings.Count(string(data), "entry ") == n {
+		t.Fatal("entries were written before the signal; nothing was buffered")
+	}
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatalf("failed to send SIGUSR2: %v", err)
+	}
+	var data []byte
+	if !waitFor(t, 2*time.Second, func() bool {
+		data, _ = os.ReadFile(path)
+		return strings.Count(string(data), "entry ") == n
+	}) {
+		t.Fatalf("expected %d entries after SIGUSR2, got %q", n, data)
+	}
+	if !strings.HasSuffix(string(data), "entry 19\n") {
+		t.Errorf("file does not end with the last entry: %q", data)
+	}
+
+	Info("still running")
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatalf("failed to send SIGUSR2: %v", err)
+	}
+	if !waitFor(t, 2*time.Second, func() bool {
+		data, _ = os.ReadFile(path)
+		return strings.Contains(string(data), "still running")
+	}) {
+		t.Errorf("logger stopped flushing after the first signal: %q", data)
+	}
+}
@@ -0,0 +1,25 @@
+// signal_windows.go
+//
+// # Chronos Logging - Signal Handling (Windows)
+//
+// Windows has no SIGUSR1, SIGUSR2 or SIGPIPE, so the handlers are not
+// installed.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+
+//go:build windows
+
+package chronos
+
+// installReopenHandler is a no-op on Windows.
+func installReopenHandler(l *Logging) {}
+
+// installFlushHandler is a no-op on Windows.
+func installFlushHandler(l *Logging) {}
+
+// installPipeHandler is a no-op on Windows.
+func installPipeHandler(l *Logging) {}
@@ -0,0 +1,227 @@
+// sink.go
+//
+// # Chronos Logging - Sinks
+//
+// Defines the `Sink` interface the writer delivers entries through. The
+// writer writes, flushes and closes the log files through a `FileSink`, and
+// `Config.CustomSinks` adds
+// user-defined sinks alongside it: each entry at or above a sink's Level is
+// written to it in queue order, flush barriers and `Config.FlushInterval`
+// flush it, and Stop closes it. Each custom sink has its own queue and
+// goroutine, so a slow Write holds up only that sink; entries that do not
+// fit in its queue are dropped and counted by SinkDropped. Network
+// destinations that need retries belong in `Config.Sinks` (see remote.go).
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"errors"
+	"fmt"
+)
+
+// customSinkQueueSize is the number of entries queued per custom sink.
+const customSinkQueueSize = 1000
+
+// Sink is a destination for log entries. Write receives each entry whose
+// level is at least Level(); Flush pushes out anything the sink buffers,
+// and Close releases it when the logger stops. Write, Flush and Close are
+// called from a single goroutine per sink.
+type Sink interface {
+	Write(log Log) error
+	Flush() error
+	Close() error
+	Level() int
+}
+
+// errNoFileLogger is returned by a FileSink that no logger created.
+var errNoFileLogger = errors.New("FileSink is not attached to a logger")
+
+// FileSink is the built-in file writer as a Sink: it writes entries to the
+// rotating log files, or to the FIFO or the file given to NewWithFile. Each
+// logger creates its own and drives it from the writer goroutine; a
+// FileSink built elsewhere writes nothing and returns an error from Write.
+type FileSink struct {
+	l *Logging
+	// line is the text last written by Write, which the writer passes on
+	// to the outputs fed alongside the files.
+	line string
+}
+
+// Write formats log and appends it to its file(s) (see Logging.write),
+// returning the first error hit.
+func (s *FileSink) Write(log Log) error {
+	l := s.l
+	if l == nil {
+		return errNoFileLogger
+	}
+	line := l.fileLine(log)
+	s.line = line
+	var err error
+	switch {
+	case l.fifo != "":
+		err = l.writeFIFO(log, line)
+	case l.file != nil:
+		err = l.writeFile(log, line)
+	case l.config.SeparateByLevel || (l.template != nil && l.template.hasLevel):
+		err = l.writeTo(log.Level, log, line)
+		if l.config.CombinedFile {
+			if combinedErr := l.writeTo("", log, line); err == nil {
+				err = combinedErr
+			}
+		}
+	default:
+		err = l.writeTo("", log, line)
+	}
+	l.lastWrite = clock()
+	if l.buffered() && log.done == nil && l.fifo == "" && l.file == nil {
+		l.countPending()
+	}
+	return err
+}
+
+// Flush writes out file output held back by batching or buffering.
+func (s *FileSink) Flush() error {
+	if s.l != nil {
+		s.l.flushPending()
+	}
+	return nil
+}
+
+// Close closes the open log files. The next entry reopens them.
+func (s *FileSink) Close() error {
+	if s.l != nil {
+		s.l.closeFiles()
+	}
+	return nil
+}
+
+// Level returns the logger's minimum severity: every entry that reaches
+// the writer is written to file.
+func (s *FileSink) Level() int {
+	if s.l == nil {
+		return 0
+	}
+	return levelSeverity(s.l.floor())
+}
+
+// customSink feeds one `Config.CustomSinks` sink from its own goroutine.
+// flush is a pending Flush request; requests made while one is pending are
+// coalesced. done is closed once the sink has been closed.
+type customSink struct {
+	index   int
+	sink    Sink
+	level   int
+	entries chan Log
+	flush   chan struct{}
+	done    chan struct{}
+}
+
+// startSinks starts a goroutine per custom sink.
+func (l *Logging) startSinks() {
+	for i, s := range l.config.CustomSinks {
+		c := &customSink{
+			index:   i,
+			sink:    s,
+			level:   levelRank(s.Level()),
+			entries: make(chan Log, customSinkQueueSize),
+			flush:   make(chan struct{}, 1),
+			done:    make(chan struct{}),
+		}
+		l.sinks = append(l.sinks, c)
+		go c.run(l.reportError)
+	}
+}
+
+// run writes queued entries and serves flush requests until the queue is
+// closed and drained, then serves a pending flush and closes the sink.
+func (c *customSink) run(onError func(error)) {
+	defer close(c.done)
+	for {
+		select {
+		case log, ok := <-c.entries:
+			if !ok {
+				select {
+				case <-c.flush:
+					c.report(onError, c.sink.Flush())
+				default:
+				}
+				c.report(onError, c.sink.Close())
+				return
+			}
+			c.report(onError, c.sink.Write(log))
+		case <-c.flush:
+			// Write what was queued before the request, then flush.
+			for n := len(c.entries); n > 0; n-- {
+				log, ok := <-c.entries
+				if !ok {
+					break
+				}
+				c.report(onError, c.sink.Write(log))
+			}
+			c.report(onError, c.sink.Flush())
+		}
+	}
+}
+
+// report passes a non-nil err from the sink to onError.
+func (c *customSink) report(onError func(error), err error) {
+	if err != nil {
+		onError(fmt.Errorf("custom sink %d: %w", c.index, err))
+	}
+}
+
+// writeSinks queues log for each custom sink whose level it meets, without
+// blocking. A sink whose queue is full drops the entry.
+func (l *Logging) writeSinks(log Log) {
+	rank := logLevels()[log.Level]
+	for _, c := range l.sinks {
+		if rank < c.level {
+			continue
+		}
+		select {
+		case c.entries <- log:
+		default:
+			l.sinkDropped.Add(1)
+		}
+	}
+}
+
+// flushSinks asks every custom sink to flush once it has written the
+// entries already queued, without waiting for it.
+func (l *Logging) flushSinks() {
+	for _, c := range l.sinks {
+		select {
+		case c.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// closeSinks lets every custom sink write the entries already queued, then
+// closes it and waits for it to finish.
+func (l *Logging) closeSinks() {
+	for _, c := range l.sinks {
+		close(c.entries)
+	}
+	for _, c := range l.sinks {
+		<-c.done
+	}
+	l.sinks = nil
+}
+
+// SinkDropped returns the number of entries the running logger has dropped
+// for custom sinks because a sink's queue was full.
+func SinkDropped() uint64 {
+	mu.Lock()
+	defer mu.Unlock()
+	l := logger.Load()
+	if l == nil {
+		return 0
+	}
+	return l.sinkDropped.Load()
+}
@@ -0,0 +1,152 @@
+// sink_test.go
+//
+// # Chronos Logging - Sink Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memSink is a Sink keeping entries in memory.
+type memSink struct {
+	mu      sync.Mutex
+	level   int
+	logs    []Log
+	flushes int
+	closed  bool
+}
+
+func (s *memSink) Write(log Log) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logs = append(s.logs, log)
+	return nil
+}
+
+func (s *memSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushes++
+	return nil
+}
+
+func (s *memSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func (s *memSink) Level() int { return s.level }
+
+var _ Sink = (*FileSink)(nil)
+
+// TestCustomSinks registers two in-memory sinks alongside the file sink and
+// asserts each receives the entries at or above its level, and is flushed
+// and closed with the logger.
+func TestCustomSinks(t *testing.T) {
+	Stop()
+	all := &memSink{}
+	warn := &memSink{level: int(LevelWarn)}
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.SyncForTest = true
+	cfg.CustomSinks = []Sink{all, warn}
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	path := PathFor(time.Now())
+	Debug("filtered")
+	Info("routine")
+	Warn("disk low")
+	logger.Load().flush()
+	Stop()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "routine") || !strings.Contains(string(data), "disk low") {
+		t.Errorf("expected both entries in the file, got %q", data)
+	}
+	if len(all.logs) != 2 || all.logs[0].Message != "routine" || all.logs[1].Message != "disk low" {
+		t.Errorf("expected INFO and WARN in the first sink, got %v", all.logs)
+	}
+	if len(warn.logs) != 1 || warn.logs[0].Message != "disk low" {
+		t.Errorf("expected only the WARN in the second sink, got %v", warn.logs)
+	}
+	for _, s := range []*memSink{all, warn} {
+		if s.flushes == 0 || !s.closed {
+			t.Errorf("expected sink to be flushed and closed, got %d flushes, closed %v", s.flushes, s.closed)
+		}
+	}
+}
+
+// gatedSink is a memSink whose Write waits until gate is closed.
+type gatedSink struct {
+	memSink
+	gate chan struct{}
+}
+
+func (s *gatedSink) Write(log Log) error {
+	<-s.gate
+	return s.memSink.Write(log)
+}
+
+// TestCustomSinkSlow blocks a custom sink and asserts file writes carry on,
+// entries beyond its queue are dropped and counted, and the queued entries
+// reach the sink once it is released.
+func TestCustomSinkSlow(t *testing.T) {
+	Stop()
+	slow := &gatedSink{gate: make(chan struct{})}
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.SyncForTest = true
+	cfg.CustomSinks = []Sink{slow}
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	path := PathFor(time.Now())
+	n := customSinkQueueSize + 10
+	for i := 0; i < n; i++ {
+		Info("entry")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "entry"); got != n {
+		t.Errorf("expected %d entries in the file while the sink is blocked, got %d", n, got)
+	}
+	if dropped := SinkDropped(); dropped == 0 {
+		t.Error("expected entries beyond the sink's queue to be dropped")
+	}
+	close(slow.gate)
+	Stop()
+	if len(slow.logs) < customSinkQueueSize || !slow.closed {
+		t.Errorf("expected the queued entries and a close, got %d entries, closed %v", len(slow.logs), slow.closed)
+	}
+}
+
+// TestFileSinkDetached asserts a FileSink no logger created reports an
+// error from Write rather than panicking.
+func TestFileSinkDetached(t *testing.T) {
+	var s FileSink
+	if err := s.Write(Log{Level: INFO, Message: "lost"}); err == nil {
+		t.Error("expected an error writing through a detached FileSink")
+	}
+	if s.Flush() != nil || s.Close() != nil || s.Level() != 0 {
+		t.Error("expected Flush, Close and Level to be no-ops")
+	}
+}
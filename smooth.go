@@ -0,0 +1,45 @@
+// smooth.go
+//
+// # Chronos Logging - Smoothed Output Rate
+//
+// Keeps bursts from spiking downstream collectors. With `Config.SmoothRate`
+// the writer spaces entries evenly, at most SmoothRate lines per second,
+// like a leaky bucket: a burst is held in the queue and drained at the
+// configured pace instead of being dropped. An entry can therefore reach
+// its file (and Tee or sinks) up to queue length / SmoothRate seconds after
+// it was logged. Once the queue is full callers block as usual, or entries
+// are dropped with `Config.MaxBlockDuration`. Console output is written by
+// the caller and is not paced.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import "time"
+
+// pacer spaces writes interval apart. It is owned by the writer goroutine.
+type pacer struct {
+	interval time.Duration
+	next     time.Time
+}
+
+// newPacer returns a pacer for rate lines per second.
+func newPacer(rate int) *pacer {
+	return &pacer{interval: time.Second / time.Duration(rate)}
+}
+
+// wait sleeps until the next write slot and reserves the one after it. An
+// idle pacer lets the first write through at once; it never saves up slots
+// for a later burst.
+func (p *pacer) wait() {
+	now := time.Now()
+	if p.next.After(now) {
+		time.Sleep(p.next.Sub(now))
+	} else {
+		p.next = now
+	}
+	p.next = p.next.Add(p.interval)
+}
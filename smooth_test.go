@@ -0,0 +1,110 @@
+// smooth_test.go
+//
+// # Chronos Logging - Smooth Rate Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stampFS is a `Config.FS` whose files record the time of every write.
+type stampFS struct {
+	mu     sync.Mutex
+	stamps []time.Time
+}
+
+func (fs *stampFS) OpenFile(name string, flag int, perm os.FileMode) (io.WriteCloser, error) {
+	return fs, nil
+}
+
+func (fs *stampFS) Write(p []byte) (int, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.stamps = append(fs.stamps, time.Now())
+	return len(p), nil
+}
+
+func (fs *stampFS) Close() error { return nil }
+
+func (fs *stampFS) times() []time.Time {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return append([]time.Time(nil), fs.stamps...)
+}
+
+// TestSmoothRate logs a burst and asserts the callers are not held up while
+// the writes reach the file spaced at the configured rate.
+func TestSmoothRate(t *testing.T) {
+	Stop()
+	const rate, n = 50, 10
+	interval := time.Second / rate
+
+	fs := &stampFS{}
+	cfg := getConfig()
+	cfg.FS = fs
+	cfg.SmoothRate = rate
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		Info("burst")
+	}
+	if elapsed := time.Since(start); elapsed > interval*n/2 {
+		t.Errorf("burst took %s to log; callers should not wait for pacing", elapsed)
+	}
+	logger.Load().flush()
+
+	stamps := fs.times()
+	if len(stamps) != n {
+		t.Fatalf("expected %d writes, got %d", n, len(stamps))
+	}
+	for i := 1; i < n; i++ {
+		if gap := stamps[i].Sub(stamps[i-1]); gap < interval/2 {
+			t.Errorf("write %d came %s after the previous one, want about %s", i, gap, interval)
+		}
+	}
+	span := stamps[n-1].Sub(stamps[0])
+	if want := interval * (n - 1); span < want-interval/2 || span > want*3 {
+		t.Errorf("burst spread over %s, want about %s", span, want)
+	}
+}
+
+// TestSmoothRateBatched asserts SmoothRate still paces entries gathered into
+// a batch by BatchWindow.
+func TestSmoothRateBatched(t *testing.T) {
+	Stop()
+	const rate, n = 50, 10
+	interval := time.Second / rate
+
+	fs := &stampFS{}
+	cfg := getConfig()
+	cfg.FS = fs
+	cfg.SmoothRate = rate
+	cfg.BatchWindow = time.Second
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		Info("burst")
+	}
+	logger.Load().flush()
+	if elapsed, want := time.Since(start), interval*(n-1); elapsed < want-interval/2 {
+		t.Errorf("batched burst written after %s, want at least about %s", elapsed, want)
+	}
+}
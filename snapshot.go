@@ -0,0 +1,86 @@
+// snapshot.go
+//
+// # Chronos Logging - Snapshots for Log Shipping
+//
+// Snapshot hands the current log file over to a log shipper: the writer
+// flushes it, renames it to a unique snapshot name, and continues on a fresh
+// file at the original path.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Snapshot moves everything logged so far to a uniquely named snapshot file
+// next to the current log file and returns its path, for a shipper to
+// upload and delete. Entries logged before the call are in the snapshot;
+// later ones go to a fresh file at the usual path. The rename runs on the
+// writer goroutine, in queue order, so it never splits an entry.
+//
+// Only the combined file is snapshotted. It returns an empty path if that
+// file does not exist yet, and an error when writing to a FIFO.
+func Snapshot() (string, error) {
+	l := logger.Load()
+	if l == nil {
+		return "", errors.New("logger not initialized")
+	}
+	var path string
+	done := make(chan error, 1)
+	l.submit(Log{
+		done: done,
+		control: func() (err error) {
+			path, err = l.snapshot()
+			return err
+		},
+	})
+	err := <-done
+	return path, err
+}
+
+// snapshot closes the combined file and renames it to a unique snapshot
+// path, which it returns. The next entry reopens the original path.
+func (l *Logging) snapshot() (string, error) {
+	if l.fifo != "" {
+		return "", errors.New("cannot snapshot a fifo")
+	}
+	if l.file != nil {
+		return "", errors.New("cannot snapshot a caller-owned file")
+	}
+	t := clock()
+	if l.latest.After(t) {
+		t = l.latest
+	}
+	current := l.filePathFor(t)
+	if h := l.handles[""]; h != nil {
+		current = h.path
+		l.closeFile("")
+	}
+	if _, err := os.Stat(current); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	ext := filepath.Ext(current)
+	base := strings.TrimSuffix(current, ext) + "_snapshot_" + l.inZone(clock()).Format("20060102T150405.000000000")
+	path := base + ext
+	for i := 1; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		path = base + "_" + strconv.Itoa(i) + ext
+	}
+	if err := os.Rename(current, path); err != nil {
+		return "", fmt.Errorf("could not snapshot log file %s: %w", current, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,99 @@
+// snapshot_test.go
+//
+// # Chronos Logging - Snapshot Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSnapshot logs, snapshots, and logs more while a concurrent goroutine
+// keeps logging, asserting every entry lands in exactly one of the snapshot
+// and the fresh file, with the snapshot holding everything logged before it.
+func TestSnapshot(t *testing.T) {
+	Stop()
+	tempDir, err := os.MkdirTemp("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+	current := logger.Load().filePathFor(time.Now())
+
+	if path, err := Snapshot(); err != nil || path != "" {
+		t.Fatalf("expected empty snapshot before any entry, got %q, %v", path, err)
+	}
+
+	const n = 200
+	stop := make(chan struct{})
+	background := make(chan int)
+	go func() {
+		i := 0
+		for ; ; i++ {
+			select {
+			case <-stop:
+				background <- i
+				return
+			default:
+				Infof("background %d", i)
+			}
+		}
+	}()
+	for i := 0; i < n; i++ {
+		Infof("before %d", i)
+	}
+	path, err := Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot failed: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		Infof("after %d", i)
+	}
+	close(stop)
+	written := <-background
+	logger.Load().flush()
+
+	if filepath.Dir(path) != tempDir || path == current {
+		t.Fatalf("unexpected snapshot path %q", path)
+	}
+	snapshot, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read snapshot: %v", err)
+	}
+	fresh, err := os.ReadFile(current)
+	if err != nil {
+		t.Fatalf("could not read current file: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if !strings.Contains(string(snapshot), fmt.Sprintf("\tbefore %d\n", i)) {
+			t.Fatalf("snapshot missing entry before %d", i)
+		}
+		if !strings.Contains(string(fresh), fmt.Sprintf("\tafter %d\n", i)) {
+			t.Fatalf("current file missing entry after %d", i)
+		}
+	}
+	if strings.Contains(string(snapshot), "\tafter ") || strings.Contains(string(fresh), "\tbefore ") {
+		t.Error("expected a clean split between snapshot and current file")
+	}
+	lines := strings.Count(string(snapshot), "\n") + strings.Count(string(fresh), "\n")
+	if want := 2*n + written; lines != want {
+		t.Errorf("expected %d lines across both files, got %d", want, lines)
+	}
+}
@@ -0,0 +1,311 @@
+// tail.go
+//
+// # Chronos Logging - Tail
+//
+// Reads entries back out of the active log file. Text files are read
+// backwards from the end, so only the tail of a large file is loaded. The
+// parser here is the inverse of `(*Logging).formatLine()`: it splits each line
+// on tabs into time, level, message, and optional fields. Lines that do not
+// start with a time carry on the message of the entry before them.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tailChunkSize is the size of the blocks Tail reads backwards from the end
+// of a text file.
+var tailChunkSize = 64 << 10
+
+// Tail returns the last n entries of the currently active log file, oldest
+// first. Output held back by buffering or batching is written out first, and
+// the file is read through `Config.FS` when set. A trailing line without a
+// newline is treated as still being written and is ignored.
+//
+// Text files are read backwards from the end until n entries are found.
+// Binary files are decoded from the start, as their timestamps are deltas
+// from the records before them; so are text files opened through an FS whose
+// files do not implement io.Seeker. JSON and CSV files are not supported.
+//
+// Tail reads the combined file, so it fails when `Config.SeparateByLevel`
+// (or a `Config.FilenameTemplate` with a level) is set without
+// `Config.CombinedFile`: entries spread over per-level files cannot be put
+// back in order, as text lines only record the time to the second.
+func Tail(n int) ([]Log, error) {
+	mu.Lock()
+	l := logger.Load()
+	mu.Unlock()
+	if l == nil {
+		return nil, errors.New("logger not initialized")
+	}
+	if n <= 0 {
+		return []Log{}, nil
+	}
+
+	if l.config.Format == FormatJSON {
+		return nil, errors.New("tail is not supported for the JSON format")
+	}
+	if l.config.Format == FormatCSV {
+		return nil, errors.New("tail is not supported for the CSV format")
+	}
+	if (l.config.SeparateByLevel || (l.template != nil && l.template.hasLevel)) && !l.config.CombinedFile {
+		return nil, errors.New("tail needs the combined file: set CombinedFile when entries are separated by level")
+	}
+
+	l.flush()
+	now := l.inZone(clock())
+	f, err := l.openRead(l.filePathFor(now))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if l.config.Format == FormatBinary {
+		content, err := io.ReadAll(f)
+		if err != nil {
+			return nil, err
+		}
+		logs, err := decodeBinary(content)
+		if err != nil && !errors.Is(err, errTruncatedRecord) {
+			return nil, err
+		}
+		if len(logs) > n {
+			logs = logs[len(logs)-n:]
+		}
+		return logs, nil
+	}
+
+	records, err := l.tailRecords(f, n)
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make([]Log, 0, len(records))
+	for _, record := range records {
+		log, err := l.parseLine(record, now)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+// openRead opens the log file at path for reading, through `Config.FS` when
+// set. Files the FS opens with os.O_RDONLY must then also implement
+// io.Reader.
+func (l *Logging) openRead(path string) (io.ReadCloser, error) {
+	if l.config.FS == nil {
+		return os.Open(path)
+	}
+	f, err := l.opener(path, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := f.(io.ReadCloser)
+	if !ok {
+		f.Close()
+		return nil, fmt.Errorf("cannot read %s: the configured FS does not support reading", path)
+	}
+	return r, nil
+}
+
+// tailRecords returns the last n records of the text file f, oldest first.
+// When f implements io.Seeker it is read backwards in tailChunkSize blocks
+// until more than n records are seen, the first of which may be missing the
+// start of its entry; otherwise the whole file is read.
+func (l *Logging) tailRecords(f io.Reader, n int) ([]string, error) {
+	seeker, ok := f.(io.Seeker)
+	if !ok {
+		content, err := io.ReadAll(f)
+		if err != nil {
+			return nil, err
+		}
+		return l.lastRecords(content, true, n), nil
+	}
+	offset, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	var content []byte
+	for {
+		start := max(offset-int64(tailChunkSize), 0)
+		chunk := make([]byte, offset-start)
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if _, err := io.ReadFull(f, chunk); err != nil {
+			return nil, err
+		}
+		content = append(chunk, content...)
+		offset = start
+		records := l.lastRecords(content, offset == 0, n+1)
+		if offset == 0 || len(records) > n {
+			if len(records) > n {
+				records = records[len(records)-n:]
+			}
+			return records, nil
+		}
+	}
+}
+
+// lastRecords splits content, the end of a text file, into records and
+// returns up to the last n. A trailing line without a newline is dropped, as
+// is the leading partial line unless content starts at the beginning of the
+// file.
+func (l *Logging) lastRecords(content []byte, whole bool, n int) []string {
+	text := string(content)
+	i := strings.LastIndexByte(text, '\n')
+	if i < 0 {
+		return []string{}
+	}
+	text = text[:i]
+	if !whole {
+		j := strings.IndexByte(text, '\n')
+		if j < 0 {
+			return []string{}
+		}
+		text = text[j+1:]
+	}
+	records := l.splitRecords(strings.Split(text, "\n"))
+	if len(records) > n {
+		records = records[len(records)-n:]
+	}
+	return records
+}
+
+// splitRecords groups lines into one record per entry. A line that does not
+// start with a time column continues the message of the entry before it, and
+// loses the `Config.MultilineIndent` prefix added when it was written.
+func (l *Logging) splitRecords(lines []string) []string {
+	records := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if len(records) > 0 && !l.startsEntry(line) {
+			records[len(records)-1] += "\n" + strings.TrimPrefix(line, l.config.MultilineIndent)
+			continue
+		}
+		records = append(records, line)
+	}
+	return records
+}
+
+// startsEntry reports whether line begins with the time column of an entry.
+func (l *Logging) startsEntry(line string) bool {
+	layout := timeLayout
+	if l.config.IncludeWriteTime {
+		layout = preciseLayout
+	}
+	ts, _, ok := strings.Cut(line, l.separator())
+	if !ok {
+		return false
+	}
+	_, err := time.Parse(layout, ts)
+	return err == nil
+}
+
+// parseLine parses a single text line written by the writer. The line only
+// records the time of day, so the date is taken from day.
+func (l *Logging) parseLine(line string, day time.Time) (Log, error) {
+	var log Log
+	sep := l.separator()
+	if l.config.IncludeWriteTime {
+		parts := strings.SplitN(line, sep, 2)
+		if len(parts) < 2 {
+			return Log{}, fmt.Errorf("malformed log line: %q", line)
+		}
+		ts, err := parseTimeOfDay(parts[0], preciseLayout, day)
+		if err != nil {
+			return Log{}, err
+		}
+		log.TimeStamp = ts
+		line = parts[1]
+	}
+
+	n := 4
+	if l.config.IncludeSeverityNumber {
+		n = 5
+	}
+	parts := strings.SplitN(line, sep, n)
+	if len(parts) < n-1 {
+		return Log{}, fmt.Errorf("malformed log line: %q", line)
+	}
+	if l.config.IncludeSeverityNumber {
+		// The severity column is derived from the level.
+		parts = append(parts[:2], parts[3:]...)
+	}
+	layout := timeLayout
+	if l.config.IncludeWriteTime {
+		layout = preciseLayout
+	}
+	ts, err := parseTimeOfDay(parts[0], layout, day)
+	if err != nil {
+		return Log{}, err
+	}
+	if l.config.IncludeWriteTime {
+		log.WriteTime = ts
+	} else {
+		log.TimeStamp = ts
+	}
+	log.Level = parts[1]
+	log.Message = parts[2]
+	if len(parts) == 4 {
+		log.Fields = parseFields(parts[3])
+	}
+	return log, nil
+}
+
+// parseTimeOfDay parses a time of day in layout and places it on day's date.
+func parseTimeOfDay(s, layout string, day time.Time) (time.Time, error) {
+	tod, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed log time %q: %w", s, err)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), tod.Hour(), tod.Minute(), tod.Second(), tod.Nanosecond(), day.Location()), nil
+}
+
+// parseFields parses the space-separated key=value pairs written by
+// formatFields(), unquoting quoted values. Values are returned as strings.
+func parseFields(s string) Fields {
+	fields := Fields{}
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			return fields
+		}
+		end := strings.IndexAny(s, "= ")
+		if end < 0 {
+			fields[s] = ""
+			return fields
+		}
+		k := s[:end]
+		if s[end] == ' ' {
+			fields[k] = ""
+			s = s[end:]
+			continue
+		}
+		s = s[end+1:]
+		var v string
+		if strings.HasPrefix(s, `"`) {
+			if q, err := strconv.QuotedPrefix(s); err == nil {
+				v, _ = strconv.Unquote(q)
+				fields[k] = v
+				s = s[len(q):]
+				continue
+			}
+		}
+		v, s, _ = strings.Cut(s, " ")
+		fields[k] = v
+	}
+}
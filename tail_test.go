@@ -0,0 +1,206 @@
+// tail_test.go
+//
+// # Chronos Logging - Tail Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTail writes known entries and asserts Tail parses the last n back,
+// ignoring a partially written final line.
+func TestTail(t *testing.T) {
+	Stop()
+
+	tempDir, err := os.MkdirTemp("", "tail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cfg := getConfig()
+	cfg.Location = tempDir
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	Info("first")
+	Warn("second")
+	Error("third")
+	time.Sleep(100 * time.Millisecond)
+
+	// Simulate an entry that is still being written.
+	path := filepath.Join(tempDir, logger.Load().filename(time.Now()))
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("12:00:00\tINFO\tpart")
+	f.Close()
+
+	logs, err := Tail(2)
+	if err != nil {
+		t.Fatalf("Tail failed: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(logs))
+	}
+	if logs[0].Level != WARN || logs[0].Message != "second" {
+		t.Errorf("unexpected first entry: %+v", logs[0])
+	}
+	if logs[1].Level != ERROR || logs[1].Message != "third" {
+		t.Errorf("unexpected second entry: %+v", logs[1])
+	}
+	if d := time.Since(logs[1].TimeStamp); d < 0 || d > time.Minute {
+		t.Errorf("unexpected timestamp %v", logs[1].TimeStamp)
+	}
+}
+
+// TestParseLineFields ensures fields written by formatLine are parsed back.
+func TestParseLineFields(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	in := Log{TimeStamp: now, Level: INFO, Message: "hello world", Fields: Fields{"a": "1", "b": "two"}}
+
+	l := newLogging(getConfig(), logLevels()[INFO])
+	out, err := l.parseLine(l.formatLine(in), now)
+	if err != nil {
+		t.Fatalf("parseLine failed: %v", err)
+	}
+	if !out.TimeStamp.Equal(now) || out.Level != in.Level || out.Message != in.Message {
+		t.Errorf("round trip mismatch: %+v", out)
+	}
+	if out.Fields["a"] != "1" || out.Fields["b"] != "two" {
+		t.Errorf("unexpected fields: %v", out.Fields)
+	}
+}
+
+// readableFS is a memFS whose files can also be opened for reading.
+type readableFS struct {
+	*memFS
+}
+
+// readOnlyFile is a snapshot of a memFile opened with os.O_RDONLY.
+type readOnlyFile struct {
+	io.ReadSeeker
+}
+
+func (readOnlyFile) Write(p []byte) (int, error) { return 0, errors.New("read only") }
+func (readOnlyFile) Close() error                { return nil }
+
+func (fs readableFS) OpenFile(name string, flag int, perm os.FileMode) (io.WriteCloser, error) {
+	if flag == os.O_RDONLY {
+		f := fs.file(name)
+		if f == nil {
+			return nil, os.ErrNotExist
+		}
+		return readOnlyFile{strings.NewReader(f.String())}, nil
+	}
+	return fs.memFS.OpenFile(name, flag, perm)
+}
+
+// TestTailMultiline asserts Tail writes out buffered output, reads through
+// Config.FS, and reads multi-line messages and quoted field values back
+// whole.
+func TestTailMultiline(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.Location = filepath.Join(t.TempDir(), "memory")
+	cfg.FS = readableFS{newMemFS()}
+	cfg.MultilineIndent = "  "
+	cfg.FlushInterval = time.Hour
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	Info("first")
+	WithFields(Fields{"user": "jane doe", "path": "a=b"}).Error("one\ntwo\nthree")
+	Warn("last")
+
+	logs, err := Tail(2)
+	if err != nil {
+		t.Fatalf("Tail failed: %v", err)
+	}
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(logs))
+	}
+	if logs[0].Level != ERROR || logs[0].Message != "one\ntwo\nthree" {
+		t.Errorf("unexpected multi-line entry: %+v", logs[0])
+	}
+	if logs[0].Fields["user"] != "jane doe" || logs[0].Fields["path"] != "a=b" {
+		t.Errorf("unexpected fields: %v", logs[0].Fields)
+	}
+	if logs[1].Message != "last" {
+		t.Errorf("unexpected last entry: %+v", logs[1])
+	}
+}
+
+// TestTailReadsBackwards shrinks the read block so Tail has to step back
+// through several blocks, across multi-line entries, and asserts it finds
+// the same entries as a whole-file read.
+func TestTailReadsBackwards(t *testing.T) {
+	Stop()
+	defer func(size int) { tailChunkSize = size }(tailChunkSize)
+	tailChunkSize = 16
+
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	for i := 0; i < 10; i++ {
+		Infof("entry %d\nsecond line of entry %d", i, i)
+	}
+	for _, n := range []int{1, 3, 10, 20} {
+		logs, err := Tail(n)
+		if err != nil {
+			t.Fatalf("Tail(%d) failed: %v", n, err)
+		}
+		want := min(n, 10)
+		if len(logs) != want {
+			t.Fatalf("Tail(%d): expected %d entries, got %d", n, want, len(logs))
+		}
+		for i, log := range logs {
+			k := 10 - want + i
+			if msg := fmt.Sprintf("entry %d\nsecond line of entry %d", k, k); log.Message != msg {
+				t.Errorf("Tail(%d): entry %d: expected %q, got %q", n, i, msg, log.Message)
+			}
+		}
+	}
+}
+
+// TestTailSeparateByLevel asserts Tail reports a clear error when only
+// per-level files are written.
+func TestTailSeparateByLevel(t *testing.T) {
+	Stop()
+	cfg := getConfig()
+	cfg.Location = t.TempDir()
+	cfg.SeparateByLevel = true
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	Info("only in the info file")
+	if _, err := Tail(1); err == nil || !strings.Contains(err.Error(), "CombinedFile") {
+		t.Errorf("expected an error naming CombinedFile, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+// tee.go
+//
+// # Chronos Logging - Tee Writer
+//
+// Copies every formatted file line to `Config.Tee`, for example to dual-write
+// into another logger or collector during a migration. Tee writes happen on
+// their own goroutine through a bounded queue so a slow tee never blocks the
+// file writer; lines are dropped when the queue is full.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"fmt"
+	"io"
+)
+
+// teeQueueSize bounds the number of lines waiting for the tee writer.
+const teeQueueSize = 1000
+
+// teeWriter forwards lines to an io.Writer on a dedicated goroutine.
+type teeWriter struct {
+	w     io.Writer
+	lines chan string
+	done  chan struct{}
+}
+
+// newTeeWriter starts the goroutine forwarding lines to w.
+func newTeeWriter(w io.Writer, onError func(error)) *teeWriter {
+	t := &teeWriter{
+		w:     w,
+		lines: make(chan string, teeQueueSize),
+		done:  make(chan struct{}),
+	}
+	go func() {
+		defer close(t.done)
+		for line := range t.lines {
+			if _, err := io.WriteString(t.w, line); err != nil {
+				onError(fmt.Errorf("could not write to tee: %w", err))
+			}
+		}
+	}()
+	return t
+}
+
+// send queues a line without blocking. It reports false if the line was
+// dropped because the queue is full.
+func (t *teeWriter) send(line string) bool {
+	select {
+	case t.lines <- line:
+		return true
+	default:
+		return false
+	}
+}
+
+// close drains the queue and waits for the goroutine to exit.
+func (t *teeWriter) close() {
+	close(t.lines)
+	<-t.done
+}
@@ -0,0 +1,79 @@
+// tee_test.go
+//
+// # Chronos Logging - Tee Writer Tests
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// TestTeeReceivesLines asserts the tee receives exactly the lines written to
+// the log file.
+func TestTeeReceivesLines(t *testing.T) {
+	Stop()
+
+	tempDir, err := os.MkdirTemp("", "tee")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	tee := &syncBuffer{}
+	cfg := getConfig()
+	cfg.Location = tempDir
+	cfg.Tee = tee
+	l := newLogging(cfg, logLevels()[INFO])
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	Info("first")
+	Warn("second")
+	Error("third")
+	path := l.filePathFor(time.Now())
+	Stop()
+	<-done
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read log file: %v", err)
+	}
+	if tee.String() != string(content) {
+		t.Errorf("tee output differs from file:\nfile: %q\ntee:  %q", content, tee.String())
+	}
+	if strings.Count(tee.String(), "\n") != 3 {
+		t.Errorf("expected 3 lines in tee, got %q", tee.String())
+	}
+}
@@ -0,0 +1,112 @@
+// template.go
+//
+// # Chronos Logging - Filename Templates
+//
+// Parses `Config.FilenameTemplate`, which replaces the built-in filename
+// scheme. Templates mix literal text with tokens in braces:
+//
+//	{app}          Config.AppName
+//	{level}        lowercased level of the entry's stream (empty for the
+//	               combined file)
+//	{instance}     Config.InstanceID
+//	{date}         date part for Config.FilePeriod, as in the default names
+//	{date:LAYOUT}  the entry's timestamp formatted with a Go time layout
+//
+// For example "{app}-{level}-{date:2006/01/02}.log". A template may contain
+// path separators; missing directories are created when files are opened.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// templatePart is a literal (token empty) or a token of a filename template.
+type templatePart struct {
+	literal string
+	token   string
+	layout  string
+}
+
+// filenameTemplate is a parsed `Config.FilenameTemplate`.
+type filenameTemplate struct {
+	parts    []templatePart
+	hasLevel bool
+}
+
+// parseFilenameTemplate parses s, rejecting unknown tokens and unbalanced
+// braces.
+func parseFilenameTemplate(s string) (*filenameTemplate, error) {
+	tmpl := &filenameTemplate{}
+	rest := s
+	for rest != "" {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			tmpl.parts = append(tmpl.parts, templatePart{literal: rest})
+			break
+		}
+		if rest[open] == '}' {
+			return nil, fmt.Errorf("invalid filename template %q: unexpected '}'", s)
+		}
+		if open > 0 {
+			tmpl.parts = append(tmpl.parts, templatePart{literal: rest[:open]})
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("invalid filename template %q: unclosed '{'", s)
+		}
+		token, layout, hasLayout := strings.Cut(rest[open+1:open+end], ":")
+		switch token {
+		case "app", "level", "instance":
+			if hasLayout {
+				return nil, fmt.Errorf("invalid filename template %q: {%s} takes no layout", s, token)
+			}
+		case "date":
+			if hasLayout && layout == "" {
+				return nil, fmt.Errorf("invalid filename template %q: empty date layout", s)
+			}
+		default:
+			return nil, fmt.Errorf("invalid filename template %q: unknown token {%s}", s, token)
+		}
+		if token == "level" {
+			tmpl.hasLevel = true
+		}
+		tmpl.parts = append(tmpl.parts, templatePart{token: token, layout: layout})
+		rest = rest[open+end+1:]
+	}
+	if len(tmpl.parts) == 0 {
+		return nil, fmt.Errorf("invalid filename template %q: empty", s)
+	}
+	return tmpl, nil
+}
+
+// render produces the filename for timestamp t and level stream.
+func (tmpl *filenameTemplate) render(l *Logging, t time.Time, level string) string {
+	var sb strings.Builder
+	for _, p := range tmpl.parts {
+		switch p.token {
+		case "":
+			sb.WriteString(p.literal)
+		case "app":
+			sb.WriteString(l.config.AppName)
+		case "level":
+			sb.WriteString(strings.ToLower(level))
+		case "instance":
+			sb.WriteString(l.config.InstanceID)
+		case "date":
+			if p.layout == "" {
+				sb.WriteString(l.datePart(t))
+			} else {
+				sb.WriteString(t.Format(p.layout))
+			}
+		}
+	}
+	return sb.String()
+}
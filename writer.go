@@ -0,0 +1,478 @@
+// writer.go
+//
+// # Chronos Logging - File Writer
+//
+// Implements the background writer goroutine. The writer keeps its log
+// file(s) open between entries, switching files when the rotation period
+// changes and closing handles after `Config.IdleTimeout` of inactivity.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// opener opens a log file for appending. It mirrors os.OpenFile so tests can
+// substitute an in-memory implementation.
+type opener func(name string, flag int, perm os.FileMode) (io.WriteCloser, error)
+
+// openOSFile is the default opener backed by the operating system.
+func openOSFile(name string, flag int, perm os.FileMode) (io.WriteCloser, error) {
+	return os.OpenFile(name, flag, perm)
+}
+
+// FileSystem opens log files for the writer (see `Config.FS`). OpenFile has
+// the semantics of os.OpenFile: the writer opens files with
+// os.O_APPEND|os.O_CREATE|os.O_WRONLY and expects writes to append to any
+// existing content. Implementations create parent directories as needed.
+type FileSystem interface {
+	OpenFile(name string, flag int, perm os.FileMode) (io.WriteCloser, error)
+}
+
+// logHandle is an open log file and the path it was opened at. last is the
+// timestamp of the previous binary record written through the handle,
+// pending holds output gathered while batching (see batch.go), and hash is
+// the previous line's audit hash (see audit.go).
+type logHandle struct {
+	file    io.WriteCloser
+	path    string
+	last    time.Time
+	pending []byte
+	hash    string
+}
+
+// start runs the background writer loop. It takes entries from l.logChan in
+// queue order, so each goroutine's entries keep program order, and hands
+// each one to handle, which writes it through the FileSink and the other
+// outputs. Between entries it serves flush ticks, reopen requests and idle
+// closes. I/O errors are passed to reportError() and the loop carries on; it
+// returns once Stop closes the channel, closing the files and outputs.
+func (l *Logging) start() {
+	defer l.compressing.Wait()
+	defer l.files.Close()
+	l.openOutputs()
+	defer l.closeOutputs()
+
+	var flushTick <-chan time.Time
+	if l.config.FlushInterval > 0 {
+		ticker := time.NewTicker(l.config.FlushInterval)
+		defer ticker.Stop()
+		flushTick = ticker.C
+	}
+
+	var idle <-chan time.Time
+	if l.config.IdleTimeout > 0 {
+		ticker := time.NewTicker(l.config.IdleTimeout)
+		defer ticker.Stop()
+		idle = ticker.C
+	}
+
+	for {
+		select {
+		case log, ok := <-l.logChan:
+			if !ok {
+				return
+			}
+			if l.batchable(log) {
+				next, more, open := l.writeBatch(log)
+				if !open {
+					return
+				}
+				if !more {
+					l.drained()
+					continue
+				}
+				log = next
+			}
+			err := l.handle(log)
+			l.drained()
+			if log.done != nil {
+				log.done <- err
+			}
+		case <-flushTick:
+			l.files.Flush()
+			l.flushSinks()
+		case <-l.reopen:
+			// The next entry reopens each file at its original path.
+			l.files.Close()
+		case <-idle:
+			if len(l.handles) > 0 && clock().Sub(l.lastWrite) >= l.config.IdleTimeout {
+				l.files.Close()
+			}
+		}
+	}
+}
+
+// openOutputs starts the outputs fed alongside the files (custom sinks, tee,
+// CloudWatch and remote sinks) and sweeps expired retention files.
+// closeOutputs stops them.
+func (l *Logging) openOutputs() {
+	l.startSinks()
+	if l.config.Tee != nil {
+		l.tee = newTeeWriter(l.config.Tee, l.reportError)
+	}
+	if l.config.CloudWatch != nil {
+		sink, err := newCloudWatchSink(*l.config.CloudWatch, l.config.RemoteBuffer, l.config.RemoteTimeout, l.reportError, &l.remoteDropped)
+		if err != nil {
+			l.reportError(err)
+		} else {
+			l.cloudwatch = sink
+		}
+	}
+	if sinks := l.remoteSinks(); len(sinks) > 0 {
+		l.remote = newRemoteDispatcher(sinks, l.config.RemoteBuffer, l.config.RemoteTimeout, l.reportError, &l.remoteDropped)
+	}
+
+	if len(l.config.Retention) > 0 {
+		l.sweep("")
+	}
+}
+
+// closeOutputs stops the outputs started by openOutputs, letting each send
+// what it has already been given.
+func (l *Logging) closeOutputs() {
+	l.closeSinks()
+	if l.remote != nil {
+		l.remote.close()
+	}
+	if l.cloudwatch != nil {
+		l.cloudwatch.close()
+	}
+	if l.tee != nil {
+		l.tee.close()
+	}
+}
+
+// handle processes one entry taken from the queue: a log entry is written,
+// a control entry runs its function, and a bare barrier flushes buffered
+// output.
+func (l *Logging) handle(log Log) error {
+	switch {
+	case log.Level != "":
+		if l.pacer != nil {
+			l.pacer.wait()
+		}
+		return l.write(log)
+	case log.control != nil:
+		return log.control()
+	default:
+		l.files.Flush()
+		l.flushSinks()
+		return nil
+	}
+}
+
+// drained runs after each processed entry, or batch of entries. Once the
+// queue is empty the console is flushed and `Config.OnDrain` is called.
+func (l *Logging) drained() {
+	if l.dropsDrained() {
+		l.console.flush()
+		if l.config.OnDrain != nil {
+			l.config.OnDrain()
+		}
+	}
+}
+
+// dropsDrained logs a drop warning when a threshold has been crossed (see
+// warnDrops) and, once the queue is empty, stops dropping (see overflow.go).
+// It reports whether the queue is empty. Batches run it after every entry,
+// so drops are reported and ended without waiting for the batch to finish.
+func (l *Logging) dropsDrained() bool {
+	l.warnDrops()
+	if len(l.logChan) > 0 {
+		return false
+	}
+	l.resumeBlocking()
+	return true
+}
+
+// flush blocks until every entry queued before the call has been written.
+func (l *Logging) flush() {
+	done := make(chan error, 1)
+	l.submit(Log{done: done})
+	<-done
+}
+
+// write appends a single entry to its file(s) through the FileSink: the
+// combined file and, with `Config.SeparateByLevel`, the file for the
+// entry's level. It then hands the entry to the custom sinks and the other
+// outputs. It returns the first error hit opening, writing, or (for entries
+// carrying done) syncing a file; errors are also passed to reportError().
+func (l *Logging) write(log Log) error {
+	if l.config.IncludeWriteTime {
+		log.WriteTime = clock()
+	}
+	if log.TimeStamp.After(l.latest) {
+		l.latest = log.TimeStamp
+	}
+	err := l.files.Write(log)
+	line := l.files.line
+	l.writeSinks(log)
+
+	if l.tee != nil {
+		l.tee.send(line)
+	}
+	if l.cloudwatch != nil && !l.cloudwatch.send(log, line) {
+		l.remoteDropped.Add(1)
+	}
+	if l.remote != nil {
+		l.remote.deliver(log)
+	}
+	l.warnUnknownPeriod()
+	return err
+}
+
+// warnUnknownPeriod writes a WARN, once, after a filename has fallen back to
+// a daily date because `Config.FilePeriod` is not a known period.
+func (l *Logging) warnUnknownPeriod() {
+	if l.periodWarned || !l.unknownPeriod.Load() {
+		return
+	}
+	l.periodWarned = true
+	warn := Log{
+		TimeStamp: clock(),
+		Level:     WARN,
+		Message:   fmt.Sprintf("unknown file period %q, using daily log files", l.config.FilePeriod),
+	}
+	l.writeConsole(warn)
+	l.write(warn)
+}
+
+// writeTo appends the entry to the file for the given level stream ("" for
+// the combined file), opening or switching files as needed. Entries with a
+// retention class use the class subdirectory and their own handles. Text
+// files get line; binary files get the entry's binary record instead.
+// Entries carrying done are synced to disk before writeTo returns.
+func (l *Logging) writeTo(level string, log Log, line string) error {
+	dir, key := l.dirFor(l.latest), level
+	if log.retention != "" {
+		dir = filepath.Join(l.path, log.retention)
+		key = log.retention + "/" + level
+	}
+	// An entry stamped before the latest one (the clock stepped back) stays
+	// in the current file rather than reopening an older one.
+	fullpath := filepath.Join(dir, l.filenameFor(l.latest, level))
+	h := l.handles[key]
+	if h == nil || h.path != fullpath {
+		l.closeFile(key)
+		if prev, seen := l.lastPaths[key]; prev != fullpath {
+			if l.lastPaths == nil {
+				l.lastPaths = map[string]string{}
+			}
+			l.lastPaths[key] = fullpath
+			if l.config.Compress {
+				if !seen {
+					prev = l.leftoverFile(level, log.retention, fullpath)
+				}
+				if prev != "" {
+					l.compressRotated(prev)
+				}
+			}
+		}
+		var err error
+		if h, err = l.openFile(fullpath); err != nil {
+			// If the log file can't be opened, report the error and continue.
+			l.reportError(err)
+			return err
+		}
+		if l.handles == nil {
+			l.handles = map[string]*logHandle{}
+		}
+		l.handles[key] = h
+		if len(l.config.Retention) > 0 {
+			l.sweep(fullpath)
+		}
+		l.announce(key, fullpath)
+	}
+
+	if l.batching || (l.buffered() && log.done == nil) {
+		h.pending = append(h.pending, l.encode(h, log, line)...)
+		return nil
+	}
+	l.flushHandle(h)
+	if _, err := io.WriteString(h.file, l.encode(h, log, line)); err != nil {
+		err = fmt.Errorf("could not write to log file %s: %w", fullpath, err)
+		l.reportError(err)
+		return err
+	}
+	if log.done != nil {
+		if f, ok := h.file.(interface{ Sync() error }); ok {
+			if err := f.Sync(); err != nil {
+				err = fmt.Errorf("could not sync log file %s: %w", fullpath, err)
+				l.reportError(err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// encode returns the bytes to write through h for the entry: line for text
+// files (with its hash under `Config.AuditChain`), or the entry's binary
+// record.
+func (l *Logging) encode(h *logHandle, log Log, line string) string {
+	if l.config.Format != FormatBinary {
+		if l.config.AuditChain {
+			return l.chain(h, line)
+		}
+		return line
+	}
+	data := encodeBinary(nil, log, h.last, l.config.FieldOrder)
+	h.last = log.TimeStamp
+	return string(data)
+}
+
+// writeFIFO writes the entry to the named pipe set by `Config.FIFO` (or a
+// pipe at `Config.Location`). While no reader has the pipe open, entries are
+// dropped and the failure is reported once. A write that times out drops the
+// entry; any other write error closes the pipe so the next entry reopens it.
+func (l *Logging) writeFIFO(log Log, line string) error {
+	h := l.handles[""]
+	if h == nil {
+		file, err := openFIFO(l.fifo)
+		if err != nil {
+			err = fmt.Errorf("could not open fifo %s: %w", l.fifo, err)
+			if !l.fifoDown {
+				l.reportError(err)
+				l.fifoDown = true
+			}
+			return err
+		}
+		l.fifoDown = false
+		h = &logHandle{file: file, path: l.fifo}
+		if l.handles == nil {
+			l.handles = map[string]*logHandle{}
+		}
+		l.handles[""] = h
+	}
+
+	if _, err := io.WriteString(h.file, l.encode(h, log, line)); err != nil {
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			l.closeFile("")
+		}
+		err = fmt.Errorf("could not write to fifo %s: %w", l.fifo, err)
+		l.reportError(err)
+		return err
+	}
+	return nil
+}
+
+// filePathFor returns the full path of the combined log file for timestamp t.
+func (l *Logging) filePathFor(t time.Time) string {
+	return filepath.Join(l.dirFor(t), l.filename(t))
+}
+
+// openFile opens fullpath in append mode, creating it if needed.
+func (l *Logging) openFile(fullpath string) (*logHandle, error) {
+	created := false
+	if l.config.FileOwner != nil {
+		_, err := os.Stat(fullpath)
+		created = os.IsNotExist(err)
+	}
+
+	if l.config.FS == nil && filepath.Dir(fullpath) != l.path {
+		// Templates and retention classes place files in subdirectories.
+		if err := os.MkdirAll(filepath.Dir(fullpath), 0755); err != nil {
+			return nil, fmt.Errorf("could not create log directory for %s: %w", fullpath, err)
+		}
+	}
+
+	file, err := l.opener(fullpath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open log file %s: %w", fullpath, err)
+	}
+	if l.config.Format == FormatCSV {
+		if err := l.writeCSVHeader(file); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("could not write header to log file %s: %w", fullpath, err)
+		}
+	}
+
+	if created {
+		if err := chown(fullpath, l.config.FileOwner); err != nil {
+			l.reportError(fmt.Errorf("could not change owner of log file %s: %w", fullpath, err))
+		}
+	}
+	h := &logHandle{file: file, path: fullpath}
+	if l.config.AuditChain {
+		h.hash = lastChainHash(fullpath)
+	}
+	return h, nil
+}
+
+// leftoverFile returns the file of the stream's previous period, if it is
+// still uncompressed, when the stream opens its first file. That file was
+// rotated away from while the process was not running, so it is compressed
+// like any other rotated file.
+func (l *Logging) leftoverFile(level, retention, current string) string {
+	if l.config.FS != nil {
+		return ""
+	}
+	prev := l.previousPeriod(l.latest)
+	dir := l.dirFor(prev)
+	if retention != "" {
+		dir = filepath.Join(l.path, retention)
+	}
+	path := filepath.Join(dir, l.filenameFor(prev, level))
+	if path == current {
+		return ""
+	}
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		return ""
+	}
+	return path
+}
+
+// announce passes fullpath to `Config.OnFileCreate`, on its own goroutine,
+// the first time the stream key opens it. Reopening the same file after an
+// idle close is not announced again.
+func (l *Logging) announce(key, fullpath string) {
+	if l.config.OnFileCreate == nil || l.announced[key] == fullpath {
+		return
+	}
+	if l.announced == nil {
+		l.announced = map[string]string{}
+	}
+	l.announced[key] = fullpath
+	go l.config.OnFileCreate(fullpath)
+}
+
+// closeFile closes the handle for the given stream key, if open.
+func (l *Logging) closeFile(level string) {
+	h := l.handles[level]
+	if h == nil {
+		return
+	}
+	l.flushHandle(h)
+	if err := h.file.Close(); err != nil {
+		l.reportError(fmt.Errorf("could not close log file %s: %w", h.path, err))
+	}
+	delete(l.handles, level)
+}
+
+// closeFiles closes every open handle.
+func (l *Logging) closeFiles() {
+	for level := range l.handles {
+		l.closeFile(level)
+	}
+}
+
+// reportError passes a writer error to `Config.ErrorHandler`, or prints it to
+// stderr when no handler is configured.
+func (l *Logging) reportError(err error) {
+	if l.config.ErrorHandler != nil {
+		l.config.ErrorHandler(err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+}
@@ -0,0 +1,601 @@
+// writer_test.go
+//
+// # Chronos Logging - File Writer Tests
+//
+// Covers handle caching and idle closing in the background writer, using a
+// controllable clock and an in-memory opener.
+//
+// Author: Mark Oxley
+// Company: DaggerTech
+// Created: 2025
+//
+// Copyright (c) 2025 DaggerTech. All rights reserved.
+package chronos
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for tests.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+// memFile is an in-memory log file that records whether it was closed.
+type memFile struct {
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	closed bool
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.buf.Write(p)
+}
+
+func (f *memFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+func (f *memFile) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+func (f *memFile) String() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.buf.String()
+}
+
+// memFS is an opener that hands out memFiles and records every open.
+type memFS struct {
+	mu    sync.Mutex
+	files map[string]*memFile
+	opens []string
+}
+
+func newMemFS() *memFS {
+	return &memFS{files: map[string]*memFile{}}
+}
+
+func (fs *memFS) open(name string, flag int, perm os.FileMode) (io.WriteCloser, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	f, ok := fs.files[name]
+	if !ok || f.isClosed() {
+		f = &memFile{}
+		if ok {
+			f.buf.WriteString(fs.files[name].String())
+		}
+		fs.files[name] = f
+	}
+	fs.opens = append(fs.opens, name)
+	return f, nil
+}
+
+// OpenFile lets memFS stand in for the OS as `Config.FS`.
+func (fs *memFS) OpenFile(name string, flag int, perm os.FileMode) (io.WriteCloser, error) {
+	return fs.open(name, flag, perm)
+}
+
+func (fs *memFS) file(name string) *memFile {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.files[name]
+}
+
+func (fs *memFS) openCount() int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return len(fs.opens)
+}
+
+// useClock installs c as the package clock and returns a restore function.
+func useClock(c *fakeClock) func() {
+	clock = c.Now
+	return func() { clock = time.Now }
+}
+
+// waitFor polls cond until it is true or the timeout expires.
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return cond()
+}
+
+// TestWriterReusesHandle ensures consecutive entries in the same period are
+// written through a single open handle.
+func TestWriterReusesHandle(t *testing.T) {
+	Stop()
+	fc := &fakeClock{t: time.Date(2025, 3, 1, 10, 0, 0, 0, time.Local)}
+	defer useClock(fc)()
+
+	fs := newMemFS()
+	l := newLogging(getConfig(), logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	Info("one")
+	Info("two")
+	Stop()
+	<-done
+
+	if fs.openCount() != 1 {
+		t.Errorf("expected 1 open, got %d", fs.openCount())
+	}
+	f := fs.file(l.filePathFor(fc.Now()))
+	if f == nil || !f.isClosed() {
+		t.Fatal("expected file to be closed on Stop")
+	}
+}
+
+// TestWriterIdleClose advances the clock past the idle timeout and asserts
+// the cached handle is closed, then reopened lazily on the next entry.
+func TestWriterIdleClose(t *testing.T) {
+	Stop()
+	fc := &fakeClock{t: time.Date(2025, 3, 1, 23, 59, 0, 0, time.Local)}
+	defer useClock(fc)()
+
+	fs := newMemFS()
+	cfg := getConfig()
+	cfg.IdleTimeout = 10 * time.Millisecond
+	l := newLogging(cfg, logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+	defer func() {
+		Stop()
+		<-done
+	}()
+
+	Info("before idle")
+	name := l.filePathFor(fc.Now())
+	if !waitFor(t, time.Second, func() bool { return fs.file(name) != nil }) {
+		t.Fatal("expected file to be opened")
+	}
+
+	// Real time passes but the clock does not, so the handle stays open.
+	time.Sleep(50 * time.Millisecond)
+	if fs.file(name).isClosed() {
+		t.Fatal("handle closed before idle timeout elapsed")
+	}
+
+	fc.Advance(time.Hour)
+	if !waitFor(t, time.Second, func() bool { return fs.file(name).isClosed() }) {
+		t.Fatal("expected idle handle to be closed")
+	}
+
+	Info("after idle")
+	if !waitFor(t, time.Second, func() bool { return fs.openCount() == 2 }) {
+		t.Errorf("expected lazy reopen, got %d opens", fs.openCount())
+	}
+}
+
+// TestSeparateByLevel logs one entry per level and asserts each lands in its
+// own level file, with the combined file written only when requested.
+func TestSeparateByLevel(t *testing.T) {
+	for _, combined := range []bool{false, true} {
+		Stop()
+		tempDir, err := os.MkdirTemp("", "bylevel")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(tempDir)
+
+		cfg := getConfig()
+		cfg.Location = tempDir
+		cfg.SeparateByLevel = true
+		cfg.CombinedFile = combined
+		l := newLogging(cfg, logLevels()[DEBUG])
+		logger.Store(l)
+		done := make(chan struct{})
+		go func() {
+			l.start()
+			close(done)
+		}()
+
+		now := time.Now()
+		Debug("debug entry")
+		Info("info entry")
+		Warn("warn entry")
+		Error("error entry")
+		Fatal("fatal entry")
+		Stop()
+		<-done
+
+		entries, err := os.ReadDir(tempDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := 5
+		if combined {
+			want = 6
+		}
+		if len(entries) != want {
+			t.Fatalf("combined=%v: expected %d files, got %d", combined, want, len(entries))
+		}
+		for _, level := range []string{DEBUG, INFO, WARN, ERROR, FATAL} {
+			content, err := os.ReadFile(filepath.Join(tempDir, l.filenameFor(now, level)))
+			if err != nil {
+				t.Fatalf("missing file for %s: %v", level, err)
+			}
+			lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+			if len(lines) != 1 || !strings.Contains(lines[0], strings.ToLower(level)+" entry") {
+				t.Errorf("unexpected content for %s: %q", level, content)
+			}
+		}
+		if combined {
+			content, err := os.ReadFile(l.filePathFor(now))
+			if err != nil {
+				t.Fatalf("missing combined file: %v", err)
+			}
+			if strings.Count(string(content), "\n") != 5 {
+				t.Errorf("expected 5 lines in combined file, got %q", content)
+			}
+		}
+	}
+}
+
+// gatedWriter blocks every write until the gate is closed.
+type gatedWriter struct {
+	memFile
+	gate chan struct{}
+}
+
+func (g *gatedWriter) Write(p []byte) (int, error) {
+	<-g.gate
+	return g.memFile.Write(p)
+}
+
+// TestIncludeWriteTime stalls the writer and asserts the write time lags the
+// call time by at least the stall.
+func TestIncludeWriteTime(t *testing.T) {
+	Stop()
+	gw := &gatedWriter{gate: make(chan struct{})}
+	cfg := getConfig()
+	cfg.IncludeWriteTime = true
+	l := newLogging(cfg, logLevels()[INFO])
+	l.opener = func(string, int, os.FileMode) (io.WriteCloser, error) { return gw, nil }
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	// The first entry blocks the writer, so the second waits in the queue.
+	Info("blocker")
+	Info("stalled")
+	time.Sleep(200 * time.Millisecond)
+	close(gw.gate)
+	Stop()
+	<-done
+
+	lines := strings.Split(strings.TrimSuffix(gw.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", gw.String())
+	}
+	line := lines[1]
+	log, err := l.parseLine(line, time.Now())
+	if err != nil {
+		t.Fatalf("could not parse %q: %v", line, err)
+	}
+	if log.Message != "stalled" {
+		t.Errorf("unexpected message %q", log.Message)
+	}
+	if lag := log.WriteTime.Sub(log.TimeStamp); lag < 150*time.Millisecond {
+		t.Errorf("expected write time to lag call time by the stall, got %v (%q)", lag, line)
+	}
+}
+
+// TestSingleGoroutineOrder logs a numbered sequence from one goroutine, with
+// a rotation halfway through, and asserts the files preserve it exactly.
+func TestSingleGoroutineOrder(t *testing.T) {
+	Stop()
+	fc := &fakeClock{t: time.Date(2025, 3, 1, 10, 0, 0, 0, time.Local)}
+	defer useClock(fc)()
+
+	fs := newMemFS()
+	l := newLogging(getConfig(), logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	const n = 500
+	first := fc.Now()
+	for i := 0; i < n; i++ {
+		if i == n/2 {
+			fc.Advance(time.Hour)
+		}
+		Infof("entry %d", i)
+	}
+	second := fc.Now()
+	Stop()
+	<-done
+
+	content := fs.file(l.filePathFor(first)).String() + fs.file(l.filePathFor(second)).String()
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if len(lines) != n {
+		t.Fatalf("expected %d lines, got %d", n, len(lines))
+	}
+	for i, line := range lines {
+		if want := fmt.Sprintf("\tentry %d", i); !strings.HasSuffix(line, want) {
+			t.Fatalf("line %d out of order: %q", i, line)
+		}
+	}
+}
+
+// TestClockBackwardsNoRotation steps the clock back across an hour boundary
+// and asserts the writer keeps the current file instead of reopening the
+// previous hour's file.
+func TestClockBackwardsNoRotation(t *testing.T) {
+	Stop()
+	fc := &fakeClock{t: time.Date(2025, 3, 1, 11, 0, 30, 0, time.Local)}
+	defer useClock(fc)()
+
+	fs := newMemFS()
+	l := newLogging(getConfig(), logLevels()[INFO])
+	l.opener = fs.open
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	current := l.filePathFor(fc.Now())
+	Info("before step")
+	fc.Advance(-time.Minute)
+	previous := l.filePathFor(fc.Now())
+	Info("after step")
+	Stop()
+	<-done
+
+	if fs.openCount() != 1 || fs.file(previous) != nil {
+		t.Fatalf("expected no backward rotation, got opens %v", fs.opens)
+	}
+	content := fs.file(current).String()
+	if !strings.Contains(content, "before step\n") || !strings.HasSuffix(content, "after step\n") {
+		t.Errorf("expected both entries in the current file, got %q", content)
+	}
+}
+
+// TestConfigFSRotation logs across an hour boundary through an in-memory
+// `Config.FS` and asserts one file per hour is created in memory and
+// nothing on disk.
+func TestConfigFSRotation(t *testing.T) {
+	Stop()
+	fc := &fakeClock{t: time.Date(2025, 3, 1, 10, 59, 0, 0, time.Local)}
+	defer useClock(fc)()
+
+	fs := newMemFS()
+	cfg := getConfig()
+	cfg.Location = filepath.Join(t.TempDir(), "memory")
+	cfg.FS = fs
+	cfg.SyncForTest = true
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	first := PathFor(fc.Now())
+	Info("ten")
+	fc.Advance(2 * time.Minute)
+	second := PathFor(fc.Now())
+	Info("eleven")
+	Stop()
+
+	if first == second {
+		t.Fatalf("expected rotation to a new path, got %s twice", first)
+	}
+	if len(fs.files) != 2 {
+		t.Fatalf("expected 2 files in memory, got %v", fs.opens)
+	}
+	if got := fs.file(first).String(); !strings.HasSuffix(got, "ten\n") {
+		t.Errorf("first file: got %q", got)
+	}
+	if got := fs.file(second).String(); !strings.HasSuffix(got, "eleven\n") {
+		t.Errorf("second file: got %q", got)
+	}
+	if _, err := os.Stat(cfg.Location); !os.IsNotExist(err) {
+		t.Errorf("expected nothing on disk at %s, got %v", cfg.Location, err)
+	}
+}
+
+// TestLocationsRoundRobin logs across four simulated hours with two
+// directories and asserts the files alternate between them, matching
+// PathFor.
+func TestLocationsRoundRobin(t *testing.T) {
+	Stop()
+	fc := &fakeClock{t: time.Date(2025, 3, 1, 10, 0, 0, 0, time.Local)}
+	defer useClock(fc)()
+
+	var dirs []string
+	for i := 0; i < 2; i++ {
+		dir, err := os.MkdirTemp("", "locations")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		dirs = append(dirs, dir)
+	}
+
+	cfg := getConfig()
+	cfg.Locations = dirs
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	defer Stop()
+
+	var paths []string
+	for hour := 0; hour < 4; hour++ {
+		Infof("hour %d", hour)
+		paths = append(paths, PathFor(fc.Now()))
+		fc.Advance(time.Hour)
+	}
+	logger.Load().flush()
+
+	for i, path := range paths {
+		if i > 0 && filepath.Dir(path) == filepath.Dir(paths[i-1]) {
+			t.Errorf("expected consecutive periods in different directories, got %v", paths)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("missing file for hour %d: %v", i, err)
+		}
+		if !strings.Contains(string(content), fmt.Sprintf("\thour %d\n", i)) {
+			t.Errorf("unexpected content in %s: %q", path, content)
+		}
+	}
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 2 {
+			t.Errorf("expected 2 files in %s, got %d", dir, len(entries))
+		}
+	}
+}
+
+// TestOnDrain holds the writer while a burst is queued and asserts OnDrain
+// fires once, after every entry has been written.
+func TestOnDrain(t *testing.T) {
+	Stop()
+	gw := &gatedWriter{gate: make(chan struct{})}
+	var drains []int
+	var drainMu sync.Mutex
+	cfg := getConfig()
+	cfg.OnDrain = func() {
+		drainMu.Lock()
+		defer drainMu.Unlock()
+		drains = append(drains, strings.Count(gw.String(), "\n"))
+	}
+	l := newLogging(cfg, logLevels()[INFO])
+	l.opener = func(string, int, os.FileMode) (io.WriteCloser, error) { return gw, nil }
+	logger.Store(l)
+	done := make(chan struct{})
+	go func() {
+		l.start()
+		close(done)
+	}()
+
+	const n = 100
+	for i := 0; i < n; i++ {
+		Infof("burst %d", i)
+	}
+	close(gw.gate)
+	if !waitFor(t, time.Second, func() bool {
+		drainMu.Lock()
+		defer drainMu.Unlock()
+		return len(drains) > 0
+	}) {
+		t.Fatal("expected OnDrain to fire")
+	}
+	time.Sleep(50 * time.Millisecond)
+	Stop()
+	<-done
+
+	drainMu.Lock()
+	defer drainMu.Unlock()
+	if len(drains) != 1 || drains[0] != n {
+		t.Errorf("expected one drain after %d lines, got %v", n, drains)
+	}
+}
+
+// TestOnFileCreate logs across an hour boundary, closing and reopening the
+// first file on the way, and asserts the callback fires once per distinct file.
+func TestOnFileCreate(t *testing.T) {
+	Stop()
+	fc := &fakeClock{t: time.Date(2025, 3, 1, 10, 58, 0, 0, time.Local)}
+	defer useClock(fc)()
+
+	var mu sync.Mutex
+	var created []string
+	fs := newMemFS()
+	cfg := getConfig()
+	cfg.Location = filepath.Join(t.TempDir(), "memory")
+	cfg.FS = fs
+	cfg.SyncForTest = true
+	cfg.OnFileCreate = func(path string) {
+		mu.Lock()
+		defer mu.Unlock()
+		created = append(created, path)
+	}
+	if err := Init(cfg); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	first := PathFor(fc.Now())
+	Info("one")
+	Info("two")
+	// Close the file as the idle timer would; reopening it is no new file.
+	logger.Load().inlineMu.Lock()
+	logger.Load().closeFiles()
+	logger.Load().inlineMu.Unlock()
+	Info("three")
+	fc.Advance(2 * time.Minute)
+	second := PathFor(fc.Now())
+	Info("four")
+	Stop()
+
+	got := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), created...)
+	}
+	waitFor(t, time.Second, func() bool { return len(got()) >= 2 })
+	time.Sleep(20 * time.Millisecond)
+	paths := got()
+	sort.Strings(paths)
+	if len(paths) != 2 || paths[0] != first || paths[1] != second {
+		t.Errorf("expected callbacks for %s and %s, got %v", first, second, paths)
+	}
+	if fs.openCount() < 3 {
+		t.Errorf("expected the idle file to be reopened, got opens %v", fs.opens)
+	}
+}